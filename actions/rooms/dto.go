@@ -2,16 +2,37 @@ package rooms
 
 import (
 	"errors"
+	"influence_game/internal/game/engine"
 )
 
 type CreateRoomDTO struct {
-	Nickname string `json:"nickname"`
+	Nickname string                 `json:"nickname"`
+	Settings *engine.SettingsUpdate `json:"settings,omitempty"`
 }
 
 func (dto *CreateRoomDTO) Validate() error {
 	if dto.Nickname == "" {
 		return errors.New("nickname_is_required")
 	}
+	return dto.RoomSettings().Validate()
+}
+
+// RoomSettings returns the default settings with whatever the admin asked for on top.
+func (dto *CreateRoomDTO) RoomSettings() engine.Settings {
+	if dto.Settings == nil {
+		return engine.DefaultSettings()
+	}
+	return engine.DefaultSettings().Apply(*dto.Settings)
+}
+
+type UpdateSettingsDTO struct {
+	engine.SettingsUpdate
+}
+
+func (dto *UpdateSettingsDTO) Validate() error {
+	if dto.SettingsUpdate == (engine.SettingsUpdate{}) {
+		return errors.New("settings_are_required")
+	}
 	return nil
 }
 
@@ -37,3 +58,73 @@ func (dto *DeclareActionDTO) Validate() error {
 	}
 	return nil
 }
+
+type BlockActionDTO struct {
+	Role string `json:"role"`
+}
+
+func (dto *BlockActionDTO) Validate() error {
+	if dto.Role == "" {
+		return errors.New("role_is_required")
+	}
+	return nil
+}
+
+type ExchangeCardsDTO struct {
+	Keep []string `json:"keep"`
+}
+
+func (dto *ExchangeCardsDTO) Validate() error {
+	if len(dto.Keep) == 0 {
+		return errors.New("keep_is_required")
+	}
+	return nil
+}
+
+type RevealInfluenceDTO struct {
+	InfluenceIndex *int `json:"influenceIndex"`
+}
+
+func (dto *RevealInfluenceDTO) Validate() error {
+	if dto.InfluenceIndex == nil {
+		return errors.New("influence_index_is_required")
+	}
+	return nil
+}
+
+type ShowInfluenceDTO struct {
+	InfluenceIndex *int `json:"influenceIndex"`
+}
+
+func (dto *ShowInfluenceDTO) Validate() error {
+	if dto.InfluenceIndex == nil {
+		return errors.New("influence_index_is_required")
+	}
+	return nil
+}
+
+type DecideExaminationDTO struct {
+	ForceExchange *bool `json:"forceExchange"`
+}
+
+func (dto *DecideExaminationDTO) Validate() error {
+	if dto.ForceExchange == nil {
+		return errors.New("force_exchange_is_required")
+	}
+	return nil
+}
+
+type DraftInfluenceDTO struct {
+	Role string `json:"role"`
+}
+
+func (dto *DraftInfluenceDTO) Validate() error {
+	if dto.Role == "" {
+		return errors.New("role_is_required")
+	}
+	return nil
+}
+
+type RematchDTO struct {
+	WinnerStarts bool `json:"winnerStarts"`
+}
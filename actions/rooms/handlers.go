@@ -1,7 +1,10 @@
 package rooms
 
 import (
+	"errors"
 	"influence_game/internal/game"
+	"influence_game/internal/game/engine"
+	"io"
 	"strings"
 
 	"github.com/gobuffalo/buffalo"
@@ -39,7 +42,7 @@ func (controller *RoomsController) CreateRoom(ctx buffalo.Context) error {
 
 	nickname := dto.Nickname
 
-	newGamePublicInfo, err := controller.Store.CreateGameRoom(nickname)
+	newGamePublicInfo, err := controller.Store.CreateGameRoom(nickname, dto.RoomSettings())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create new game room.")
 		return ctx.Render(500, renderer.JSON(map[string]any{
@@ -88,25 +91,55 @@ func (controller *RoomsController) JoinRoom(ctx buffalo.Context) error {
 	return ctx.Render(200, renderer.JSON(onboardingResult))
 }
 
-func (controller *RoomsController) StartGame(ctx buffalo.Context) error {
-	log.Info().Msg("Starting game.")
+func (controller *RoomsController) UpdateSettings(ctx buffalo.Context) error {
+	log.Info().Msg("Updating room settings.")
 	gameID := ctx.Param("gameID")
 
-	authHeader := ctx.Request().Header.Get("Authorization")
-	const prefix = "Bearer "
+	var dto UpdateSettingsDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind update settings request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
 
-	if !strings.HasPrefix(authHeader, prefix) {
-		log.Error().Msg("Missing or invalid Authorization header.")
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate update settings request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
 		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": "missing or invalid Authorization header",
+			"error": err.Error(),
 		}))
 	}
 
-	sessionToken := strings.TrimPrefix(authHeader, prefix)
-	if sessionToken == "" {
-		log.Error().Msg("Empty bearer token.")
+	updatedGameState, err := controller.Store.UpdateSettings(gameID, sessionToken, dto.SettingsUpdate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update room settings.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Updated room settings successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
+func (controller *RoomsController) StartGame(ctx buffalo.Context) error {
+	log.Info().Msg("Starting game.")
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
 		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": "empty bearer token",
+			"error": err.Error(),
 		}))
 	}
 
@@ -123,6 +156,90 @@ func (controller *RoomsController) StartGame(ctx buffalo.Context) error {
 	return ctx.Render(200, renderer.JSON(updatedGameState))
 }
 
+func (controller *RoomsController) LeaveRoom(ctx buffalo.Context) error {
+	log.Info().Msg("Leaving game room.")
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	if err := controller.Store.LeaveRoom(gameID, sessionToken); err != nil {
+		log.Error().Err(err).Msg("Failed to leave game room.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Left game room successfully.")
+
+	return ctx.Render(204, nil)
+}
+
+func (controller *RoomsController) KickPlayer(ctx buffalo.Context) error {
+	log.Info().Msg("Kicking player.")
+	gameID := ctx.Param("gameID")
+	targetID := ctx.Param("playerID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	updatedGameState, err := controller.Store.KickPlayer(gameID, sessionToken, targetID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to kick player.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Kicked player successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
+func (controller *RoomsController) Rematch(ctx buffalo.Context) error {
+	log.Info().Msg("Starting rematch.")
+	gameID := ctx.Param("gameID")
+
+	// The body is optional, an empty one keeps the default options.
+	var dto RematchDTO
+	if err := ctx.Bind(&dto); err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Msg("Failed to bind rematch request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	updatedGameState, err := controller.Store.Rematch(gameID, sessionToken, dto.WinnerStarts)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to start rematch.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Rematch started successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
 func (controller *RoomsController) DeclareAction(ctx buffalo.Context) error {
 	log.Info().Msg("Declaring action.")
 	gameID := ctx.Param("gameID")
@@ -142,21 +259,11 @@ func (controller *RoomsController) DeclareAction(ctx buffalo.Context) error {
 		}))
 	}
 
-	authHeader := ctx.Request().Header.Get("Authorization")
-	const prefix = "Bearer "
-
-	if !strings.HasPrefix(authHeader, prefix) {
-		log.Error().Msg("Missing or invalid Authorization header.")
-		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": "missing or invalid Authorization header",
-		}))
-	}
-
-	sessionToken := strings.TrimPrefix(authHeader, prefix)
-	if sessionToken == "" {
-		log.Error().Msg("Empty bearer token.")
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
 		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": "empty bearer token",
+			"error": err.Error(),
 		}))
 	}
 
@@ -179,3 +286,394 @@ func (controller *RoomsController) DeclareAction(ctx buffalo.Context) error {
 
 	return ctx.Render(200, renderer.JSON(currentGameState))
 }
+
+func (controller *RoomsController) PassAction(ctx buffalo.Context) error {
+	log.Info().Msg("Passing on pending action.")
+	gameID := ctx.Param("gameID")
+	actionID := ctx.Param("actionID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.PassAction(gameID, actionID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to pass on action.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Passed on action successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) BlockAction(ctx buffalo.Context) error {
+	log.Info().Msg("Blocking pending action.")
+	gameID := ctx.Param("gameID")
+	actionID := ctx.Param("actionID")
+
+	var dto BlockActionDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind block action request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate block action request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.BlockAction(gameID, actionID, sessionToken, dto.Role)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to block action.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Blocked action successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) ChallengeAction(ctx buffalo.Context) error {
+	log.Info().Msg("Challenging pending action.")
+	gameID := ctx.Param("gameID")
+	actionID := ctx.Param("actionID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.ChallengeAction(gameID, actionID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to challenge action.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Challenged action successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) ExchangeCards(ctx buffalo.Context) error {
+	log.Info().Msg("Exchanging cards.")
+	gameID := ctx.Param("gameID")
+
+	var dto ExchangeCardsDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind exchange cards request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate exchange cards request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.ExchangeCards(gameID, sessionToken, dto.Keep)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to exchange cards.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Exchanged cards successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) DraftInfluence(ctx buffalo.Context) error {
+	log.Info().Msg("Drafting influence.")
+	gameID := ctx.Param("gameID")
+
+	var dto DraftInfluenceDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind draft influence request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate draft influence request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.DraftInfluence(gameID, sessionToken, dto.Role)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to draft influence.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Drafted influence successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) DecideExamination(ctx buffalo.Context) error {
+	log.Info().Msg("Deciding examination.")
+	gameID := ctx.Param("gameID")
+
+	var dto DecideExaminationDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind decide examination request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate decide examination request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.DecideExamination(gameID, sessionToken, *dto.ForceExchange)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to decide examination.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Decided examination successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) RevealInfluence(ctx buffalo.Context) error {
+	log.Info().Msg("Revealing influence.")
+	gameID := ctx.Param("gameID")
+
+	var dto RevealInfluenceDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind reveal influence request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate reveal influence request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.RevealInfluence(gameID, sessionToken, *dto.InfluenceIndex)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reveal influence.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Revealed influence successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) ShowInfluence(ctx buffalo.Context) error {
+	log.Info().Msg("Showing influence.")
+	gameID := ctx.Param("gameID")
+
+	var dto ShowInfluenceDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind show influence request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": "invalid_json",
+		}))
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate show influence request.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	currentGameState, err := controller.Store.ShowInfluence(gameID, sessionToken, *dto.InfluenceIndex)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to show influence.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Showed influence successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) Forfeit(ctx buffalo.Context) error {
+	log.Info().Msg("Forfeiting game.")
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	updatedGameState, err := controller.Store.Forfeit(gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to forfeit game.")
+		return ctx.Render(400, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	log.Info().Msg("Forfeited game successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
+func (controller *RoomsController) GetRoom(ctx buffalo.Context) error {
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	gameState, err := controller.Store.GetGameState(gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get game state.")
+		return ctx.Render(statusForReadError(err), renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	return ctx.Render(200, renderer.JSON(gameState))
+}
+
+func (controller *RoomsController) GetMe(ctx buffalo.Context) error {
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := bearerToken(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Missing or invalid Authorization header.")
+		return ctx.Render(401, renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	privateView, err := controller.Store.GetPrivateView(gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get player view.")
+		return ctx.Render(statusForReadError(err), renderer.JSON(map[string]any{
+			"error": err.Error(),
+		}))
+	}
+
+	return ctx.Render(200, renderer.JSON(privateView))
+}
+
+// statusForReadError maps the errors of the read endpoints to an HTTP status.
+func statusForReadError(err error) int {
+	switch {
+	case errors.Is(err, game.ErrInvalidSession):
+		return 401
+	case errors.Is(err, game.ErrGameNotFound), errors.Is(err, engine.ErrPlayerNotFound):
+		return 404
+	default:
+		return 500
+	}
+}
+
+// bearerToken extracts the session token from the "Authorization: Bearer <token>" header.
+func bearerToken(ctx buffalo.Context) (string, error) {
+	authHeader := ctx.Request().Header.Get("Authorization")
+	const prefix = "Bearer "
+
+	if !strings.HasPrefix(authHeader, prefix) {
+		return "", errors.New("missing or invalid Authorization header")
+	}
+
+	sessionToken := strings.TrimPrefix(authHeader, prefix)
+	if sessionToken == "" {
+		return "", errors.New("empty bearer token")
+	}
+
+	return sessionToken, nil
+}
@@ -4,11 +4,24 @@ import "github.com/gobuffalo/buffalo"
 
 func Register(app *buffalo.App, controller *RoomsController) {
 	app.POST("/rooms", controller.CreateRoom)
+	app.GET("/rooms/{gameID}", controller.GetRoom)
+	app.GET("/rooms/{gameID}/me", controller.GetMe)
 	app.POST("/rooms/{joinCode}/join", controller.JoinRoom)
+	app.PATCH("/rooms/{gameID}/settings", controller.UpdateSettings)
 	app.POST("/rooms/{gameID}/start", controller.StartGame)
-	// app.DELETE("/rooms/{joinCode}/leave", controller.DeleteRoom)
-	// app.POST("/rooms/{joinCode}/leave", controller.LeaveRoom)
+	app.POST("/rooms/{gameID}/rematch", controller.Rematch)
+	app.POST("/rooms/{gameID}/leave", controller.LeaveRoom)
+	app.DELETE("/rooms/{gameID}/players/{playerID}", controller.KickPlayer)
 
 	// In-game routes
 	app.POST("/rooms/{gameID}/actions/declare", controller.DeclareAction)
+	app.POST("/rooms/{gameID}/actions/{actionID}/pass", controller.PassAction)
+	app.POST("/rooms/{gameID}/actions/{actionID}/block", controller.BlockAction)
+	app.POST("/rooms/{gameID}/actions/{actionID}/challenge", controller.ChallengeAction)
+	app.POST("/rooms/{gameID}/draft", controller.DraftInfluence)
+	app.POST("/rooms/{gameID}/exchange", controller.ExchangeCards)
+	app.POST("/rooms/{gameID}/examine", controller.DecideExamination)
+	app.POST("/rooms/{gameID}/influences/reveal", controller.RevealInfluence)
+	app.POST("/rooms/{gameID}/influences/show", controller.ShowInfluence)
+	app.POST("/rooms/{gameID}/forfeit", controller.Forfeit)
 }
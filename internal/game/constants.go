@@ -5,4 +5,7 @@ import "time"
 const (
 	SessionDuration = 24 * time.Hour
 	JoinCodeTTL     = 2 * time.Hour
+
+	// SeriesNextGameDelay leaves the players time to look at the result before the next game of a series is dealt.
+	SeriesNextGameDelay = 10 * time.Second
 )
@@ -0,0 +1,152 @@
+package engine
+
+import "errors"
+
+type ActionType struct {
+	Name           string      `json:"name"`
+	IsImmediate    bool        `json:"isImmediate"`
+	IsBlockable    bool        `json:"isBlockable"`
+	IsContestable  bool        `json:"isContestable"`
+	RequiresTarget bool        `json:"requiresTarget"`
+	Cost           int         `json:"cost"`
+	ClaimedRole    string      `json:"claimedRole,omitempty"`
+	DeniedRole     string      `json:"deniedRole,omitempty"` // the actor claims not to hold it
+	Draw           int         `json:"draw,omitempty"`       // cards drawn by an exchange
+	BlockableRoles []Influence `json:"blockableRoles"`
+	TargetPlayerID *string     `json:"targetPlayerId,omitempty"`
+}
+
+/*
+declareAction validates the action declared by the player whose turn it is and either
+applies it right away or opens a response window for the other players.
+*/
+func (game *Game) declareAction(command DeclareAction) ([]Event, error) {
+	actionType, err := buildActionType(command, game.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	if game.PendingAction != nil {
+		return nil, ErrActionAlreadyPending
+	}
+	if game.PendingExchange != nil {
+		return nil, ErrExchangePending
+	}
+	if game.PendingExamination != nil {
+		return nil, ErrExaminationPending
+	}
+	if err := game.ensureNoInfluenceLossPending(); err != nil {
+		return nil, err
+	}
+	if err := game.ensureNoDraftPending(); err != nil {
+		return nil, err
+	}
+
+	if err := game.ensureCanDeclare(command.PlayerID, actionType.Name); err != nil {
+		return nil, err
+	}
+	turnPlayer := game.currentPlayer()
+
+	var pendingAction *PendingAction
+
+	switch actionType.Name {
+	case "income":
+		game.takeFromTreasury(turnPlayer, 1)
+		game.advanceTurn()
+	case "foreign_aid":
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	case "coup":
+		if turnPlayer.Coins < actionType.Cost {
+			return nil, ErrNotEnoughCoins
+		}
+
+		targetPlayer, err := game.validateTarget(command.PlayerID, actionType.TargetPlayerID)
+		if err != nil {
+			return nil, err
+		}
+
+		game.payTreasury(turnPlayer, actionType.Cost)
+		game.loseInfluence(targetPlayer.ID, InfluenceLossCoup)
+		game.advanceTurn()
+	case "tax":
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	case "assassinate":
+		if turnPlayer.Coins < actionType.Cost {
+			return nil, ErrNotEnoughCoins
+		}
+
+		if _, err := game.validateTarget(command.PlayerID, actionType.TargetPlayerID); err != nil {
+			return nil, err
+		}
+
+		// The cost is paid up front: it stays spent even if the assassination gets blocked.
+		game.payTreasury(turnPlayer, actionType.Cost)
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	case "steal":
+		if _, err := game.validateTarget(command.PlayerID, actionType.TargetPlayerID); err != nil {
+			return nil, err
+		}
+
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	case "exchange":
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	case "convert":
+		if err := game.convert(turnPlayer, actionType.TargetPlayerID); err != nil {
+			return nil, err
+		}
+		game.advanceTurn()
+	case "embezzle":
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	case "examine":
+		if _, err := game.validateTarget(command.PlayerID, actionType.TargetPlayerID); err != nil {
+			return nil, err
+		}
+
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	default:
+		// Registered actions without an effect of their own are a claim the others can answer.
+		if actionType.RequiresTarget {
+			if _, err := game.validateTarget(command.PlayerID, actionType.TargetPlayerID); err != nil {
+				return nil, err
+			}
+		}
+
+		pendingAction = game.openPendingAction(command.PlayerID, *actionType, command.Now)
+	}
+
+	payload := map[string]any{
+		"actionName":      actionType.Name,
+		"isImmediate":     actionType.IsImmediate,
+		"isBlockable":     actionType.IsBlockable,
+		"isContestable":   actionType.IsContestable,
+		"requiresTarget":  actionType.RequiresTarget,
+		"targetPlayerID":  actionType.TargetPlayerID,
+		"bloackableRoles": actionType.BlockableRoles,
+	}
+	if pendingAction != nil {
+		payload["pendingAction"] = pendingAction
+	}
+
+	return []Event{
+		{Type: "action_declared", Payload: payload},
+	}, nil
+}
+
+// buildActionType looks up the declared action among the ones the room allows.
+func buildActionType(action DeclareAction, settings Settings) (*ActionType, error) {
+	actionType, ok := availableActions(settings)[action.ActionName]
+	if !ok {
+		return nil, ErrInvalidActionName
+	}
+
+	if actionType.RequiresTarget && action.TargetPlayerID == nil {
+		return nil, errors.New("target_player_is_required")
+	}
+	actionType.TargetPlayerID = action.TargetPlayerID
+
+	return &actionType, nil
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAlreadyChallenged = errors.New("action_already_challenged")
+
+// ChallengeResult describes how a challenge against a claimed role ended.
+type ChallengeResult struct {
+	ChallengerID string `json:"challengerId"`
+	ClaimantID   string `json:"claimantId"`
+	ClaimedRole  string `json:"claimedRole"`
+	ClaimDenied  bool   `json:"claimDenied,omitempty"`
+	ClaimProven  bool   `json:"claimProven"`
+	LoserID      string `json:"loserId"`
+}
+
+/*
+resolveChallenge settles a challenge against claimantID claiming role, or claiming not
+to hold it when denied is set.
+
+If the claim holds, the cards that proved it are shuffled back into the deck and replaced
+with fresh draws, and the challenger loses an influence. Otherwise the claimant loses an
+influence. Proving a denial shows the whole hand, so every face-down card is replaced.
+*/
+func (game *Game) resolveChallenge(claimantID string, role string, denied bool, challengerID string) ChallengeResult {
+	claimant := game.findPlayer(claimantID)
+
+	result := ChallengeResult{
+		ChallengerID: challengerID,
+		ClaimantID:   claimantID,
+		ClaimedRole:  role,
+		ClaimDenied:  denied,
+		ClaimProven:  claimant.hasUnrevealedRole(role) != denied,
+	}
+
+	if !result.ClaimProven {
+		game.loseInfluence(claimantID, InfluenceLossChallenge)
+		result.LoserID = claimantID
+		return result
+	}
+
+	if denied {
+		game.replaceHand(claimant)
+	} else {
+		game.replaceInfluence(claimant, role)
+	}
+	game.loseInfluence(challengerID, InfluenceLossChallenge)
+	result.LoserID = challengerID
+
+	return result
+}
+
+// replaceInfluence shuffles the player's face-down role back into the deck and draws a new card in its place.
+func (game *Game) replaceInfluence(player *Player, role string) {
+	for i := range player.Influences {
+		influence := &player.Influences[i]
+		if influence.Revealed || influence.Role != role {
+			continue
+		}
+
+		game.Deck = append(game.Deck, Influence{Role: role})
+		game.shuffleDeck()
+
+		*influence = game.Deck[0]
+		game.Deck = game.Deck[1:]
+		return
+	}
+}
+
+// replaceHand shuffles every face-down card of the player back into the deck and deals as many new ones.
+func (game *Game) replaceHand(player *Player) {
+	faceDownIndexes := player.faceDownIndexes()
+	for _, i := range faceDownIndexes {
+		game.Deck = append(game.Deck, player.Influences[i])
+	}
+	game.shuffleDeck()
+
+	for _, i := range faceDownIndexes {
+		player.Influences[i] = game.Deck[0]
+		game.Deck = game.Deck[1:]
+	}
+}
+
+// challengeAction lets the player challenge the claim that is currently open: the role
+// claimed by the action itself or, once blocked, the role claimed by the blocker.
+func (game *Game) challengeAction(command ChallengeAction) ([]Event, error) {
+	if _, err := game.respondablePendingAction(command.ActionID, command.PlayerID); err != nil {
+		return nil, err
+	}
+
+	challengeResult, closedAction, err := game.challengePendingAction(command.PlayerID, command.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{
+		{
+			Type: "action_challenged",
+			Payload: map[string]any{
+				"actionID":        command.ActionID,
+				"challengeResult": challengeResult,
+			},
+		},
+	}
+
+	if closedAction != nil {
+		events = append(events, closedActionEvent(closedAction))
+	}
+
+	return events, nil
+}
+
+/*
+challengePendingAction resolves a challenge by challengerID against the claim that is
+currently open on the pending action, and returns the action if the challenge closed it.
+
+When the action claim is proven but the action can still be blocked, the window restarts at
+now for the blockers. A successful challenge against the action refunds its cost.
+*/
+func (game *Game) challengePendingAction(challengerID string, now time.Time) (ChallengeResult, *PendingAction, error) {
+	pendingAction := game.PendingAction
+
+	if pendingAction.Status == PendingActionDeclared {
+		if !pendingAction.Action.IsContestable {
+			return ChallengeResult{}, nil, ErrActionNotContestable
+		}
+		if pendingAction.ActionChallenged {
+			return ChallengeResult{}, nil, ErrAlreadyChallenged
+		}
+	}
+
+	claimantID, claimedRole, denied := pendingAction.openClaim()
+	result := game.resolveChallenge(claimantID, claimedRole, denied, challengerID)
+
+	// The lost influence may have knocked out the last opponent, leaving nothing to resolve.
+	if game.Finished {
+		return result, nil, nil
+	}
+
+	if pendingAction.Status == PendingActionBlocked {
+		if result.ClaimProven {
+			return result, game.cancelPendingAction(), nil
+		}
+		return result, game.resolvePendingAction(), nil
+	}
+
+	if !result.ClaimProven {
+		game.takeFromTreasury(game.findPlayer(pendingAction.ActorID), pendingAction.Action.Cost)
+		return result, game.cancelPendingAction(), nil
+	}
+
+	if pendingAction.Action.IsBlockable {
+		pendingAction.ActionChallenged = true
+		pendingAction.PassedIDs = []string{}
+		game.startResponseWindow(pendingAction, now)
+
+		if !game.allEligiblePlayersPassed() {
+			return result, nil, nil
+		}
+	}
+
+	return result, game.resolvePendingAction(), nil
+}
@@ -0,0 +1,53 @@
+package engine
+
+/*
+deckSizes lists, from the smallest table up, how many copies of each role the deck
+holds. Bigger tables need a bigger deck so that everyone gets dealt two cards and an
+exchange still has cards to draw from.
+*/
+var deckSizes = []struct {
+	maxPlayers    int
+	copiesPerRole int
+}{
+	{maxPlayers: 6, copiesPerRole: 3},
+	{maxPlayers: 8, copiesPerRole: 4},
+	{maxPlayers: 10, copiesPerRole: 5},
+}
+
+// MinPlayers is the smallest table the game can be played at.
+const MinPlayers = 2
+
+// MaxPlayers returns the largest table the biggest deck can deal.
+func MaxPlayers() int {
+	return deckSizes[len(deckSizes)-1].maxPlayers
+}
+
+// NewBaseDeck returns the standard deck of 15 cards, 3 of each role.
+func NewBaseDeck() []Influence {
+	return buildDeck(deckVariants[DeckVariantBase], deckSizes[0].copiesPerRole)
+}
+
+// NewDeck returns the deck of the given variant for a table of playerCount players.
+func NewDeck(playerCount int, variant string) ([]Influence, error) {
+	roles, ok := deckVariants[variant]
+	if !ok {
+		return nil, ErrInvalidDeckVariant
+	}
+
+	for _, size := range deckSizes {
+		if playerCount <= size.maxPlayers {
+			return buildDeck(roles, size.copiesPerRole), nil
+		}
+	}
+	return nil, ErrTooManyPlayers
+}
+
+func buildDeck(roles []string, copiesPerRole int) []Influence {
+	deck := make([]Influence, 0, len(roles)*copiesPerRole)
+	for _, role := range roles {
+		for range copiesPerRole {
+			deck = append(deck, Influence{Role: role})
+		}
+	}
+	return deck
+}
@@ -0,0 +1,117 @@
+package engine
+
+import "errors"
+
+var (
+	ErrDuelNeedsTwoPlayers = errors.New("duel_needs_two_players")
+	ErrDraftPending        = errors.New("draft_pending")
+	ErrNoPendingDraft      = errors.New("no_pending_draft")
+	ErrInvalidDraftRole    = errors.New("invalid_draft_role")
+)
+
+// DuelStartingPlayerCoins is what the starting player of a duel begins with, to make up for playing first.
+const DuelStartingPlayerCoins = 1
+
+// DuelDraft holds one card of each role a duel player drafts their first influence from, only shown to them.
+type DuelDraft struct {
+	PlayerID string      `json:"playerId"`
+	Options  []Influence `json:"options"`
+}
+
+/*
+dealDuel sets up a two-player game: every player drafts one card out of one of each role,
+taken out of the deck. The undrafted cards are shuffled back and only then is every player
+dealt a second card at random, see dealAfterDrafts.
+
+The turns only begin once both players have drafted.
+*/
+func (game *Game) dealDuel(deck []Influence) []Influence {
+	roles, _ := DeckVariantRoles(game.Settings.DeckVariant)
+	game.PendingDrafts = make([]DuelDraft, 0, len(game.Players))
+
+	for _, player := range game.Players {
+		draft := DuelDraft{PlayerID: player.ID, Options: []Influence{}}
+
+		for _, role := range roles {
+			for i, influence := range deck {
+				if influence.Role == role.Name() {
+					draft.Options = append(draft.Options, influence)
+					deck = append(deck[:i], deck[i+1:]...)
+					break
+				}
+			}
+		}
+
+		game.PendingDrafts = append(game.PendingDrafts, draft)
+		player.Influences = []Influence{}
+	}
+
+	return deck
+}
+
+// dealAfterDrafts deals every duel player their random card once all the drafts are in.
+func (game *Game) dealAfterDrafts() {
+	for _, player := range game.Players {
+		player.Influences = append(player.Influences, game.Deck[0])
+		game.Deck = game.Deck[1:]
+	}
+}
+
+// ensureNoDraftPending fails while a duel player still has to draft their first influence.
+func (game *Game) ensureNoDraftPending() error {
+	if len(game.PendingDrafts) > 0 {
+		return ErrDraftPending
+	}
+	return nil
+}
+
+// draftInfluence keeps the picked role as the player's first influence and shuffles the rest of the draft back into the deck.
+func (game *Game) draftInfluence(command DraftInfluence) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	draftIndex := -1
+	for i, draft := range game.PendingDrafts {
+		if draft.PlayerID == command.PlayerID {
+			draftIndex = i
+			break
+		}
+	}
+	if draftIndex == -1 {
+		return nil, ErrNoPendingDraft
+	}
+
+	draft := game.PendingDrafts[draftIndex]
+	pickedIndex := -1
+	for i, option := range draft.Options {
+		if option.Role == command.Role {
+			pickedIndex = i
+			break
+		}
+	}
+	if pickedIndex == -1 {
+		return nil, ErrInvalidDraftRole
+	}
+
+	player := game.findPlayer(command.PlayerID)
+	player.Influences = append(player.Influences, draft.Options[pickedIndex])
+
+	game.Deck = append(game.Deck, draft.Options[:pickedIndex]...)
+	game.Deck = append(game.Deck, draft.Options[pickedIndex+1:]...)
+	game.shuffleDeck()
+
+	game.PendingDrafts = append(game.PendingDrafts[:draftIndex], game.PendingDrafts[draftIndex+1:]...)
+	if len(game.PendingDrafts) == 0 {
+		game.dealAfterDrafts()
+	}
+
+	return []Event{
+		{
+			Type: "influence_drafted",
+			Payload: map[string]any{
+				"playerID": command.PlayerID,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,330 @@
+/*
+Package engine holds the rules of the game as pure functions over Game.
+
+Nothing in here talks to Redis or to the websocket clients: Apply takes a state and a
+command and returns the next state plus the events the command produced. Persisting the
+state and delivering the events is the job of the caller.
+*/
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrUnknownCommand = errors.New("unknown_command")
+
+// Command is something a player asks the game to do.
+type Command interface {
+	isCommand()
+}
+
+type JoinGame struct {
+	PlayerID string
+	Nickname string
+}
+
+type LeaveGame struct {
+	PlayerID string
+}
+
+type KickPlayer struct {
+	PlayerID string
+	TargetID string
+}
+
+type Forfeit struct {
+	PlayerID string
+}
+
+type UpdateSettings struct {
+	PlayerID string
+	Update   SettingsUpdate
+}
+
+type StartGame struct {
+	PlayerID string
+	Seed     int64 // drives every shuffle and draw of the game
+
+	// FirstPlayerID plays first instead of a player drawn at random, when set.
+	FirstPlayerID string
+}
+
+// NextSeriesGame is sent by the server, not a player, to deal the next game of a series.
+type NextSeriesGame struct {
+	Seed int64
+}
+
+type Rematch struct {
+	PlayerID     string
+	Seed         int64
+	WinnerStarts bool
+}
+
+type DeclareAction struct {
+	PlayerID       string
+	ActionName     string
+	TargetPlayerID *string
+	Now            time.Time // when the response window opens, the engine never reads the clock
+}
+
+type PassAction struct {
+	PlayerID string
+	ActionID string
+}
+
+type BlockAction struct {
+	PlayerID string
+	ActionID string
+	Role     string
+	Now      time.Time
+}
+
+type ChallengeAction struct {
+	PlayerID string
+	ActionID string
+	Now      time.Time
+}
+
+// ExpireResponseWindow is sent by the server, not a player, once the response window may be over.
+type ExpireResponseWindow struct {
+	ActionID string
+	Now      time.Time
+}
+
+type DecideExamination struct {
+	PlayerID      string
+	ForceExchange bool
+}
+
+// ShowInfluence is the examined target picking which face-down card the Inquisitor looks at.
+type ShowInfluence struct {
+	PlayerID       string
+	InfluenceIndex int
+}
+
+type DraftInfluence struct {
+	PlayerID string
+	Role     string
+}
+
+type RevealInfluence struct {
+	PlayerID       string
+	InfluenceIndex int
+	Now            time.Time
+}
+
+type ExchangeCards struct {
+	PlayerID string
+	Keep     []string
+}
+
+func (JoinGame) isCommand()             {}
+func (LeaveGame) isCommand()            {}
+func (KickPlayer) isCommand()           {}
+func (Forfeit) isCommand()              {}
+func (UpdateSettings) isCommand()       {}
+func (StartGame) isCommand()            {}
+func (Rematch) isCommand()              {}
+func (NextSeriesGame) isCommand()       {}
+func (DeclareAction) isCommand()        {}
+func (PassAction) isCommand()           {}
+func (BlockAction) isCommand()          {}
+func (ChallengeAction) isCommand()      {}
+func (RevealInfluence) isCommand()      {}
+func (ExpireResponseWindow) isCommand() {}
+func (ExchangeCards) isCommand()        {}
+func (DecideExamination) isCommand()    {}
+func (DraftInfluence) isCommand()       {}
+func (ShowInfluence) isCommand()        {}
+
+// Event is something that happened while applying a command.
+type Event struct {
+	Type    string
+	Payload map[string]any
+
+	// RecipientID restricts the event to a single player. Empty means the whole room.
+	RecipientID string
+}
+
+// Apply runs command against a copy of state and returns the resulting state and events, or only the error.
+func Apply(state *Game, command Command) (*Game, []Event, error) {
+	game, err := state.Clone()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []Event
+
+	switch command := command.(type) {
+	case JoinGame:
+		events, err = game.joinGame(command)
+	case LeaveGame:
+		events, err = game.leaveGame(command)
+	case KickPlayer:
+		events, err = game.kickPlayer(command)
+	case Forfeit:
+		events, err = game.forfeit(command)
+	case UpdateSettings:
+		events, err = game.updateSettings(command)
+	case StartGame:
+		events, err = game.startGame(command)
+	case Rematch:
+		events, err = game.rematch(command)
+	case NextSeriesGame:
+		events, err = game.nextSeriesGame(command)
+	case DeclareAction:
+		events, err = game.declareAction(command)
+	case PassAction:
+		events, err = game.passAction(command)
+	case BlockAction:
+		events, err = game.blockAction(command)
+	case ChallengeAction:
+		events, err = game.challengeAction(command)
+	case ExpireResponseWindow:
+		events, err = game.expireResponseWindow(command)
+	case ShowInfluence:
+		events, err = game.showInfluence(command)
+	case DecideExamination:
+		events, err = game.decideExamination(command)
+	case DraftInfluence:
+		events, err = game.draftInfluence(command)
+	case RevealInfluence:
+		events, err = game.answerInfluenceLoss(command)
+	case ExchangeCards:
+		events, err = game.exchangeCards(command)
+	default:
+		err = ErrUnknownCommand
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events = append(events, game.followUpEvents(state)...)
+
+	return game, events, nil
+}
+
+// Clone returns a deep copy of the game.
+func (game *Game) Clone() (*Game, error) {
+	// The state already has to round-trip through JSON to be stored, so this keeps the copy in sync with every field.
+	data, err := json.Marshal(game)
+	if err != nil {
+		return nil, err
+	}
+
+	var clone Game
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+
+	return &clone, nil
+}
+
+// followUpEvents reports what changed between before and the current state that
+// someone has to be told about, whatever command caused it.
+func (game *Game) followUpEvents(before *Game) []Event {
+	events := []Event{}
+
+	if before.AdminID != game.AdminID && game.AdminID != "" {
+		events = append(events, Event{
+			Type: "admin_changed",
+			Payload: map[string]any{
+				"adminID": game.AdminID,
+			},
+		})
+	}
+
+	if before.PendingExchange == nil && game.PendingExchange != nil {
+		events = append(events, Event{
+			Type:        "exchange_started",
+			RecipientID: game.PendingExchange.PlayerID,
+			Payload: map[string]any{
+				"drawn":   game.PendingExchange.Drawn,
+				"options": game.exchangeOptions(),
+			},
+		})
+	}
+
+	if len(before.PendingDrafts) == 0 {
+		for _, draft := range game.PendingDrafts {
+			events = append(events, Event{
+				Type:        "draft_started",
+				RecipientID: draft.PlayerID,
+				Payload: map[string]any{
+					"options": draft.Options,
+				},
+			})
+		}
+	}
+
+	if before.PendingExamination == nil && game.PendingExamination != nil && !game.PendingExamination.Shown {
+		events = append(events, Event{
+			Type:        "examination_requested",
+			RecipientID: game.PendingExamination.TargetID,
+			Payload: map[string]any{
+				"examinerID": game.PendingExamination.ActorID,
+				"influences": game.findPlayer(game.PendingExamination.TargetID).Influences,
+			},
+		})
+	}
+
+	if (before.PendingExamination == nil || !before.PendingExamination.Shown) && game.PendingExamination != nil && game.PendingExamination.Shown {
+		examination := game.PendingExamination
+		examined := game.findPlayer(examination.TargetID).Influences[examination.InfluenceIndex]
+
+		events = append(events, Event{
+			Type:        "influence_examined",
+			RecipientID: examination.ActorID,
+			Payload: map[string]any{
+				"targetID":       examination.TargetID,
+				"influenceIndex": examination.InfluenceIndex,
+				"role":           examined.Role,
+			},
+		}, Event{
+			Type:        "influence_examined",
+			RecipientID: examination.TargetID,
+			Payload: map[string]any{
+				"examinerID":     examination.ActorID,
+				"influenceIndex": examination.InfluenceIndex,
+			},
+		})
+	}
+
+	if len(game.PendingInfluenceLosses) > 0 {
+		prompt := game.PendingInfluenceLosses[0]
+		events = append(events, Event{
+			Type:        "influence_loss_requested",
+			RecipientID: prompt.PlayerID,
+			Payload: map[string]any{
+				"reason":     prompt.Reason,
+				"influences": game.findPlayer(prompt.PlayerID).Influences,
+			},
+		})
+	}
+
+	if !before.Finished && game.Finished {
+		events = append(events, Event{
+			Type: "game_finished",
+			Payload: map[string]any{
+				"winnerID":       game.WinnerID,
+				"players":        game.Players,
+				"deckCommitment": game.DeckCommitment,
+				"seed":           game.revealedSeed(),
+			},
+		})
+	}
+
+	if !before.SeriesFinished && game.SeriesFinished {
+		events = append(events, Event{
+			Type: "series_finished",
+			Payload: map[string]any{
+				"winnerID":  game.WinnerID,
+				"standings": game.SeriesStandings(),
+			},
+		})
+	}
+
+	return events
+}
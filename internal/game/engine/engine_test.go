@@ -0,0 +1,717 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newTestGame builds a started game where player i holds hands[i] and it is p0's turn.
+func newTestGame(hands ...[]string) *Game {
+	players := make([]*Player, 0, len(hands))
+	for i, hand := range hands {
+		player := NewPlayer(fmt.Sprintf("p%d", i), fmt.Sprintf("player %d", i))
+		for _, role := range hand {
+			player.Influences = append(player.Influences, Influence{Role: role})
+		}
+		players = append(players, player)
+	}
+
+	game := &Game{
+		ID:       "game",
+		AdminID:  players[0].ID,
+		Players:  players,
+		Started:  true,
+		Settings: DefaultSettings(),
+		Deck:     []Influence{{Role: "Duke"}, {Role: "Captain"}, {Role: "Contessa"}},
+		Treasury: TreasurySupply - 2*len(players),
+	}
+	game.startTurns(0)
+
+	return game
+}
+
+func mustApply(t *testing.T, game *Game, command Command) (*Game, []Event) {
+	t.Helper()
+
+	newGame, events, err := Apply(game, command)
+	if err != nil {
+		t.Fatalf("unexpected error applying %T: %v", command, err)
+	}
+	return newGame, events
+}
+
+func passAll(t *testing.T, game *Game, exceptID string) *Game {
+	t.Helper()
+
+	for _, player := range game.Players {
+		if player.ID == exceptID || game.PendingAction == nil || game.PendingAction.hasPassed(player.ID) {
+			continue
+		}
+		game, _ = mustApply(t, game, PassAction{PlayerID: player.ID, ActionID: game.PendingAction.ID})
+	}
+	return game
+}
+
+func TestApplyDoesNotModifyState(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"})
+
+	newGame, _ := mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "income"})
+
+	if game.Players[0].Coins != 2 || game.TurnIndex != 0 {
+		t.Fatalf("original state was modified")
+	}
+	if newGame.Players[0].Coins != 3 || newGame.TurnIndex != 1 || newGame.TurnNumber != 2 {
+		t.Fatalf("income not applied: coins=%d turn=%d", newGame.Players[0].Coins, newGame.TurnIndex)
+	}
+}
+
+func TestReplayingCommandsGivesTheSameState(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	play := func() string {
+		game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"})
+		game.Settings.ResponseTimeoutSeconds = 30
+
+		game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax", Now: now})
+		game, _ = mustApply(t, game, PassAction{PlayerID: "p1", ActionID: "action-1"})
+		game, _ = mustApply(t, game, DeclareAction{PlayerID: "p1", ActionName: "foreign_aid", Now: now})
+
+		state, err := json.Marshal(game)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(state)
+	}
+
+	if first, second := play(), play(); first != second {
+		t.Fatalf("the same commands should give the same state:\n%s\n%s", first, second)
+	}
+}
+
+func TestForcedCoup(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"})
+	game.Players[0].Coins = ForcedCoupCoins
+
+	_, _, err := Apply(game, DeclareAction{PlayerID: "p0", ActionName: "income"})
+	if !errors.Is(err, ErrMustCoup) {
+		t.Fatalf("expected %v, got %v", ErrMustCoup, err)
+	}
+}
+
+func TestTaxResolvesOnceEveryonePasses(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Ambassador"})
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	game, _ = mustApply(t, game, PassAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+	if game.PendingAction == nil {
+		t.Fatalf("tax resolved before every player passed")
+	}
+
+	game = passAll(t, game, "p0")
+	if game.Players[0].Coins != 5 || game.TurnIndex != 1 {
+		t.Fatalf("tax not resolved: coins=%d turn=%d", game.Players[0].Coins, game.TurnIndex)
+	}
+}
+
+func TestFailedAssassinClaimRefundsCost(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"})
+	game.Players[0].Coins = 3
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "assassinate", TargetPlayerID: &target})
+	if game.Players[0].Coins != 0 {
+		t.Fatalf("assassination cost not paid up front")
+	}
+
+	game, events := mustApply(t, game, ChallengeAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+	if game.PendingAction != nil || game.Players[0].Coins != 3 {
+		t.Fatalf("bluffed assassination should be canceled and refunded")
+	}
+	if events[1].Type != "action_canceled" {
+		t.Fatalf("expected action_canceled, got %s", events[1].Type)
+	}
+	if len(game.PendingInfluenceLosses) != 1 || game.PendingInfluenceLosses[0].PlayerID != "p0" {
+		t.Fatalf("bluffing actor should be asked to lose an influence")
+	}
+}
+
+func TestProvenClaimReplacesCardAndChallengerLoses(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Captain"}, []string{"Contessa"}, []string{"Assassin", "Ambassador"})
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	game, _ = mustApply(t, game, ChallengeAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+
+	if game.Players[0].Coins != 5 {
+		t.Fatalf("proven tax should resolve")
+	}
+	if len(game.Deck) != 3 || len(game.Players[0].Influences) != 2 {
+		t.Fatalf("proven card should be swapped with the deck")
+	}
+	if game.Players[1].Alive {
+		t.Fatalf("challenger should lose their last influence")
+	}
+}
+
+func TestLastPlayerStandingWins(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Captain"}, []string{"Contessa"})
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	game, events := mustApply(t, game, ChallengeAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+
+	if !game.Finished || *game.WinnerID != "p0" || game.PendingAction != nil {
+		t.Fatalf("p0 should win once p1 is out")
+	}
+	if events[len(events)-1].Type != "game_finished" {
+		t.Fatalf("expected game_finished, got %s", events[len(events)-1].Type)
+	}
+}
+
+func TestStealTakesWhatTheTargetHas(t *testing.T) {
+	game := newTestGame([]string{"Captain", "Duke"}, []string{"Duke", "Contessa"}, []string{"Duke", "Duke"})
+	game.Players[1].Coins = 1
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "steal", TargetPlayerID: &target})
+	game = passAll(t, game, "p0")
+
+	if game.Players[0].Coins != 3 || game.Players[1].Coins != 0 {
+		t.Fatalf("steal moved the wrong amount: actor=%d target=%d", game.Players[0].Coins, game.Players[1].Coins)
+	}
+}
+
+func TestBlockStandsWhenNobodyChallenges(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Ambassador"})
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "foreign_aid"})
+	game, _ = mustApply(t, game, BlockAction{PlayerID: "p2", ActionID: game.PendingAction.ID, Role: "Duke"})
+	game = passAll(t, game, "p2")
+
+	if game.PendingAction != nil || game.Players[0].Coins != 2 || game.TurnIndex != 1 {
+		t.Fatalf("blocked foreign aid should be canceled")
+	}
+}
+
+func TestCoupAsksTargetToChooseInfluence(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Ambassador"})
+	game.Players[0].Coins = 7
+	target := "p1"
+
+	game, events := mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "coup", TargetPlayerID: &target})
+	if events[len(events)-1].Type != "influence_loss_requested" || events[len(events)-1].RecipientID != "p1" {
+		t.Fatalf("target should be asked privately which influence to lose")
+	}
+
+	_, _, err := Apply(game, DeclareAction{PlayerID: "p1", ActionName: "income"})
+	if !errors.Is(err, ErrInfluenceLossPending) {
+		t.Fatalf("expected %v, got %v", ErrInfluenceLossPending, err)
+	}
+
+	game, _ = mustApply(t, game, RevealInfluence{PlayerID: "p1", InfluenceIndex: 1})
+	if !game.Players[1].Influences[1].Revealed || game.Players[1].Influences[0].Revealed {
+		t.Fatalf("the chosen influence should be revealed")
+	}
+}
+
+func TestEliminatedPlayersAreSkipped(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain"}, []string{"Assassin", "Ambassador"})
+	game.Players[0].Coins = 7
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "coup", TargetPlayerID: &target})
+
+	if game.Players[1].Alive || game.TurnIndex != 2 || game.Finished {
+		t.Fatalf("p1 should be out and the turn should skip to p2")
+	}
+}
+
+func TestProjectionHidesOtherHands(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"})
+	game.Players[1].Influences[0].Revealed = true
+
+	state := game.Project(game.ViewerFor("p1"))
+	if state.Players[0].Influences[0].Role != nil {
+		t.Fatalf("p1 should not see the admin's face-down roles")
+	}
+	if state.Players[1].Influences[1].Role == nil {
+		t.Fatalf("p1 should see their own hand")
+	}
+
+	state = game.Project(game.ViewerFor("stranger"))
+	if state.Players[1].Influences[0].Role == nil || state.Players[1].Influences[1].Role != nil {
+		t.Fatalf("spectators should only see revealed roles")
+	}
+
+	game.Finished = true
+	state = game.Project(game.ViewerFor("stranger"))
+	if state.Players[0].Influences[0].Role == nil {
+		t.Fatalf("every role should be shown once the game is over")
+	}
+}
+
+func TestAdminLeavingHandsOverTheRoom(t *testing.T) {
+	game := NewGame("game", "CODE", NewPlayer("p0", "player 0"), DefaultSettings(), time.Now())
+	game.Players = append(game.Players, NewPlayer("p1", "player 1"), NewPlayer("p2", "player 2"))
+
+	game, events := mustApply(t, game, LeaveGame{PlayerID: "p0"})
+	if game.AdminID != "p1" || len(game.Players) != 2 {
+		t.Fatalf("p1 should be the new admin")
+	}
+	if events[0].Type != "player_left" || events[1].Type != "admin_changed" {
+		t.Fatalf("expected player_left and admin_changed, got %v", events)
+	}
+
+	_, _, err := Apply(game, KickPlayer{PlayerID: "p2", TargetID: "p1"})
+	if !errors.Is(err, ErrOnlyAdminCanKick) {
+		t.Fatalf("expected %v, got %v", ErrOnlyAdminCanKick, err)
+	}
+}
+
+func TestForfeitCancelsTheirActionAndMovesTheTurn(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Ambassador"})
+	deckSize := len(game.Deck)
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	game, _ = mustApply(t, game, Forfeit{PlayerID: "p0"})
+
+	if game.Players[0].Alive || !game.Players[0].Influences[0].Revealed || !game.Players[0].Influences[1].Revealed {
+		t.Fatalf("forfeiting player should be out with every influence revealed")
+	}
+	if game.PendingAction != nil || game.Players[0].Coins != 2 || game.TurnIndex != 1 {
+		t.Fatalf("their tax should be canceled and the turn moved on")
+	}
+	if len(game.Deck) != deckSize {
+		t.Fatalf("forfeited influences should not go back to the deck")
+	}
+}
+
+func TestWinningCoupKeepsTheFinalTurn(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain"})
+	game.Players[0].Coins = 7
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "coup", TargetPlayerID: &target})
+	if !game.Finished || game.TurnIndex != 0 || game.TurnNumber != 1 || game.Round != 1 {
+		t.Fatalf("the final turn should not move on: turn=%d number=%d round=%d", game.TurnIndex, game.TurnNumber, game.Round)
+	}
+}
+
+func TestEliminatedPlayerCanLeave(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain"}, []string{"Assassin", "Ambassador"})
+	game.Players[0].Coins = 7
+	game.AdminID = "p1"
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "coup", TargetPlayerID: &target})
+	if game.Players[1].Alive || game.Finished {
+		t.Fatalf("p1 should be out of a game still in progress")
+	}
+
+	game, events := mustApply(t, game, LeaveGame{PlayerID: "p1"})
+	if !game.Players[1].Left || game.AdminID != "p0" {
+		t.Fatalf("p1 should leave and hand over admin")
+	}
+	if len(events) != 2 || events[0].Type != "player_left" {
+		t.Fatalf("expected player_left then admin_changed, got %v", events)
+	}
+}
+
+func TestForfeitOfLastRespondentResolvesTheAction(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Ambassador"})
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	game, _ = mustApply(t, game, PassAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+	game, _ = mustApply(t, game, Forfeit{PlayerID: "p2"})
+
+	if game.PendingAction != nil || game.Players[0].Coins != 5 || game.TurnIndex != 1 {
+		t.Fatalf("tax should resolve once the last respondent forfeits")
+	}
+}
+
+func TestOnlyValidSettingsCanBeApplied(t *testing.T) {
+	game := NewGame("game", "CODE", NewPlayer("p0", "player 0"), DefaultSettings(), time.Now())
+	coupCost, forcedCoupCoins := 5, 4
+
+	_, _, err := Apply(game, UpdateSettings{PlayerID: "p0", Update: SettingsUpdate{CoupCost: &coupCost, ForcedCoupCoins: &forcedCoupCoins}})
+	if !errors.Is(err, ErrInvalidForcedCoupCoins) {
+		t.Fatalf("expected %v, got %v", ErrInvalidForcedCoupCoins, err)
+	}
+
+	game, _ = mustApply(t, game, UpdateSettings{PlayerID: "p0", Update: SettingsUpdate{CoupCost: &coupCost}})
+	if game.Settings.CoupCost != 5 || game.Settings.ForcedCoupCoins != ForcedCoupCoins {
+		t.Fatalf("only the coup cost should change")
+	}
+}
+
+func TestSettingsMustFitTheTreasury(t *testing.T) {
+	settings := DefaultSettings()
+	settings.MaxPlayers, settings.StartingCoins = 10, 9
+	if err := settings.Validate(); !errors.Is(err, ErrTreasuryTooSmall) {
+		t.Fatalf("expected %v, got %v", ErrTreasuryTooSmall, err)
+	}
+
+	settings = DefaultSettings()
+	settings.CoupCost, settings.ForcedCoupCoins = TreasurySupply+1, TreasurySupply+1
+	if err := settings.Validate(); !errors.Is(err, ErrInvalidCoupCost) {
+		t.Fatalf("expected %v, got %v", ErrInvalidCoupCost, err)
+	}
+}
+
+func TestExpiredResponseWindowCountsAsPassing(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Ambassador"})
+	game.Settings.ResponseTimeoutSeconds = 30
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	actionID := game.PendingAction.ID
+
+	_, _, err := Apply(game, ExpireResponseWindow{ActionID: actionID, Now: game.PendingAction.CreatedAt})
+	if !errors.Is(err, ErrResponseWindowOpen) {
+		t.Fatalf("expected %v, got %v", ErrResponseWindowOpen, err)
+	}
+
+	game, _ = mustApply(t, game, ExpireResponseWindow{ActionID: actionID, Now: game.PendingAction.ExpiresAt.Add(time.Second)})
+	if game.PendingAction != nil || game.Players[0].Coins != 5 {
+		t.Fatalf("tax should resolve once the window expires")
+	}
+}
+
+func TestResponseWindowRestartsForBlockersAndAfterInfluenceLoss(t *testing.T) {
+	game := newTestGame([]string{"Assassin", "Duke"}, []string{"Contessa", "Captain"})
+	game.Settings.ResponseTimeoutSeconds = 30
+	game.Players[0].Coins = 3
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "assassinate", TargetPlayerID: &target, Now: now})
+	actionID := game.PendingAction.ID
+
+	game, _ = mustApply(t, game, ChallengeAction{PlayerID: "p1", ActionID: actionID, Now: now.Add(10 * time.Second)})
+	if !game.PendingAction.ExpiresAt.Equal(now.Add(40 * time.Second)) {
+		t.Fatalf("the blockers should get a full window, expires at %v", game.PendingAction.ExpiresAt)
+	}
+
+	_, _, err := Apply(game, ExpireResponseWindow{ActionID: actionID, Now: now.Add(time.Minute)})
+	if !errors.Is(err, ErrInfluenceLossPending) {
+		t.Fatalf("expected %v, got %v", ErrInfluenceLossPending, err)
+	}
+
+	game, _ = mustApply(t, game, RevealInfluence{PlayerID: "p1", InfluenceIndex: 1, Now: now.Add(2 * time.Minute)})
+	if !game.PendingAction.ExpiresAt.Equal(now.Add(2*time.Minute + 30*time.Second)) {
+		t.Fatalf("the window should restart once the prompt is answered, expires at %v", game.PendingAction.ExpiresAt)
+	}
+}
+
+func TestDeckDealsEveryTableSize(t *testing.T) {
+	for playerCount := MinPlayers; playerCount <= MaxPlayers(); playerCount++ {
+		deck, err := NewDeck(playerCount, DeckVariantBase)
+		if err != nil {
+			t.Fatalf("no deck for %d players: %v", playerCount, err)
+		}
+		// Two cards each, plus two left to draw for an exchange.
+		if len(deck) < playerCount*2+2 {
+			t.Fatalf("deck of %d cards is too small for %d players", len(deck), playerCount)
+		}
+	}
+
+	if _, err := NewDeck(MaxPlayers()+1, DeckVariantBase); !errors.Is(err, ErrTooManyPlayers) {
+		t.Fatalf("expected %v, got %v", ErrTooManyPlayers, err)
+	}
+}
+
+func newReformationGame(hands ...[]string) *Game {
+	game := newTestGame(hands...)
+	game.Settings.Ruleset = RulesetReformation
+	game.assignAllegiances()
+	return game
+}
+
+func TestFactionsCannotTargetThemselves(t *testing.T) {
+	game := newReformationGame([]string{"Captain", "Duke"}, []string{"Duke", "Contessa"}, []string{"Duke", "Duke"})
+	ally := "p2"
+
+	_, _, err := Apply(game, DeclareAction{PlayerID: "p0", ActionName: "steal", TargetPlayerID: &ally})
+	if !errors.Is(err, ErrCannotTargetOwnFaction) {
+		t.Fatalf("expected %v, got %v", ErrCannotTargetOwnFaction, err)
+	}
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "convert"})
+	if game.Players[0].Allegiance != AllegianceReformist || game.TreasuryReserve != ConvertSelfCost {
+		t.Fatalf("p0 should have paid to join the reformists")
+	}
+}
+
+func TestEmbezzleTakesTheReserveUnlessTheActorHoldsADuke(t *testing.T) {
+	game := newReformationGame([]string{"Captain", "Contessa"}, []string{"Duke", "Contessa"}, []string{"Assassin", "Ambassador"})
+	game.TreasuryReserve = 3
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "embezzle"})
+	game, events := mustApply(t, game, ChallengeAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+
+	result := events[0].Payload["challengeResult"].(ChallengeResult)
+	if !result.ClaimProven || result.LoserID != "p1" {
+		t.Fatalf("p0 holds no Duke, so the challenger should lose")
+	}
+	if game.Players[0].Coins != 5 || game.TreasuryReserve != 0 {
+		t.Fatalf("embezzle should take the reserve: coins=%d reserve=%d", game.Players[0].Coins, game.TreasuryReserve)
+	}
+}
+
+func TestExamineShowsTheCardOnlyToTheInquisitor(t *testing.T) {
+	game := newTestGame([]string{"Inquisitor", "Duke"}, []string{"Captain", "Contessa"}, []string{"Assassin", "Duke"})
+	game.Settings.DeckVariant = DeckVariantInquisitor
+	target := "p1"
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "examine", TargetPlayerID: &target})
+	game, events := mustApply(t, game, PassAction{PlayerID: "p1", ActionID: game.PendingAction.ID})
+	if len(events) != 1 {
+		t.Fatalf("examination should wait for p2 to pass")
+	}
+	game, events = mustApply(t, game, PassAction{PlayerID: "p2", ActionID: game.PendingAction.ID})
+
+	requested := events[len(events)-1]
+	if requested.Type != "examination_requested" || requested.RecipientID != "p1" {
+		t.Fatalf("p1 should be asked which card to show")
+	}
+	if _, _, err := Apply(game, DecideExamination{PlayerID: "p0"}); !errors.Is(err, ErrInfluenceNotShown) {
+		t.Fatalf("expected %v, got %v", ErrInfluenceNotShown, err)
+	}
+
+	game, events = mustApply(t, game, ShowInfluence{PlayerID: "p1", InfluenceIndex: 1})
+	examined := events[len(events)-2]
+	if examined.Type != "influence_examined" || examined.RecipientID != "p0" || examined.Payload["role"] != "Contessa" {
+		t.Fatalf("the card p1 picked should be sent to p0 only")
+	}
+	if game.TurnIndex != 0 {
+		t.Fatalf("the turn should wait for the Inquisitor's decision")
+	}
+
+	game, _ = mustApply(t, game, DecideExamination{PlayerID: "p0", ForceExchange: true})
+	if game.PendingExamination != nil || game.TurnIndex != 1 || len(game.Deck) != 3 {
+		t.Fatalf("forced exchange should swap the card with the deck and end the turn")
+	}
+}
+
+func TestRegisteredRolesDriveTheAvailableActions(t *testing.T) {
+	RegisterRole(NewRole("Jester", "Blocks tax.", nil, []string{"tax"}))
+	RegisterDeckVariant("jester", []string{"Duke", "Assassin", "Ambassador", "Captain", "Jester"})
+	defer delete(deckVariants, "jester")
+	defer delete(roleRegistry, "Jester")
+
+	settings := DefaultSettings()
+	settings.DeckVariant = "jester"
+
+	actionType, err := buildActionType(DeclareAction{ActionName: "tax"}, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionType.IsBlockable || actionType.BlockableRoles[0].Role != "Jester" {
+		t.Fatalf("the Jester should block tax")
+	}
+
+	if _, err := buildActionType(DeclareAction{ActionName: "examine"}, settings); !errors.Is(err, ErrInvalidActionName) {
+		t.Fatalf("examine needs the Inquisitor, got %v", err)
+	}
+}
+
+func TestSameSeedDealsTheSameGame(t *testing.T) {
+	lobby := NewGame("game", "CODE", NewPlayer("p0", "player 0"), DefaultSettings(), time.Now())
+	lobby.Players = append(lobby.Players, NewPlayer("p1", "player 1"), NewPlayer("p2", "player 2"))
+
+	first, _ := mustApply(t, lobby, StartGame{PlayerID: "p0", Seed: 42})
+	second, _ := mustApply(t, lobby, StartGame{PlayerID: "p0", Seed: 42})
+
+	if first.TurnIndex != second.TurnIndex {
+		t.Fatalf("starting player differs: %d and %d", first.TurnIndex, second.TurnIndex)
+	}
+	for i := range first.Players {
+		if fmt.Sprint(first.Players[i].Influences) != fmt.Sprint(second.Players[i].Influences) {
+			t.Fatalf("hand of p%d differs", i)
+		}
+	}
+	if fmt.Sprint(first.Deck) != fmt.Sprint(second.Deck) {
+		t.Fatalf("deck differs")
+	}
+}
+
+func TestRevealedSeedVerifiesTheDeal(t *testing.T) {
+	lobby := NewGame("game", "CODE", NewPlayer("p0", "player 0"), DefaultSettings(), time.Now())
+	lobby.Players = append(lobby.Players, NewPlayer("p1", "player 1"), NewPlayer("p2", "player 2"))
+
+	game, events := mustApply(t, lobby, StartGame{PlayerID: "p0", Seed: 1234})
+	commitment := events[0].Payload["deckCommitment"].(string)
+	if commitment == "" || game.Project(game.ViewerFor("p1")).RevealedSeed != "" {
+		t.Fatalf("the commitment should be public and the seed secret")
+	}
+
+	if !VerifyDeal(commitment, 1234, len(game.Players), DeckVariantBase) {
+		t.Fatalf("the real seed should verify")
+	}
+	if VerifyDeal(commitment, 4321, len(game.Players), DeckVariantBase) {
+		t.Fatalf("another seed should not verify")
+	}
+}
+
+func TestCoinsAreConserved(t *testing.T) {
+	game := newTestGame([]string{"Captain", "Assassin"}, []string{"Duke", "Contessa"}, []string{"Duke", "Ambassador"})
+	target := "p1"
+
+	steps := []DeclareAction{
+		{PlayerID: "p0", ActionName: "income"},
+		{PlayerID: "p1", ActionName: "tax"},
+		{PlayerID: "p2", ActionName: "foreign_aid"},
+		{PlayerID: "p0", ActionName: "assassinate", TargetPlayerID: &target},
+	}
+
+	for _, step := range steps {
+		game, _ = mustApply(t, game, step)
+		game = passAll(t, game, step.PlayerID)
+		if game.CoinSupply() != TreasurySupply {
+			t.Fatalf("%d coins in play after %s, expected %d", game.CoinSupply(), step.ActionName, TreasurySupply)
+		}
+	}
+}
+
+func TestEmptyTreasuryPaysWhatItHas(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Duke"}, []string{"Captain", "Contessa"})
+	game.Players[1].Coins += game.Treasury - 1
+	game.Treasury = 1
+
+	game, _ = mustApply(t, game, DeclareAction{PlayerID: "p0", ActionName: "tax"})
+	game = passAll(t, game, "p0")
+
+	if game.Players[0].Coins != 3 || game.Treasury != 0 || game.CoinSupply() != TreasurySupply {
+		t.Fatalf("tax should take the last coin only: coins=%d treasury=%d", game.Players[0].Coins, game.Treasury)
+	}
+}
+
+func TestDuelDraftsTheSecondInfluence(t *testing.T) {
+	settings := DefaultSettings()
+	settings.MaxPlayers, settings.Duel = 2, true
+
+	lobby := NewGame("game", "CODE", NewPlayer("p0", "player 0"), settings, time.Now())
+	lobby.Players = append(lobby.Players, NewPlayer("p1", "player 1"))
+
+	game, events := mustApply(t, lobby, StartGame{PlayerID: "p0", Seed: 7})
+	if len(game.PendingDrafts) != 2 || len(game.PendingDrafts[0].Options) != 5 || events[len(events)-1].Type != "draft_started" {
+		t.Fatalf("each player should be offered one card of each role")
+	}
+	if game.currentPlayer().Coins != DuelStartingPlayerCoins || game.CoinSupply() != TreasurySupply {
+		t.Fatalf("the starting player should begin with %d coin", DuelStartingPlayerCoins)
+	}
+
+	_, _, err := Apply(game, DeclareAction{PlayerID: game.currentPlayer().ID, ActionName: "income"})
+	if !errors.Is(err, ErrDraftPending) {
+		t.Fatalf("expected %v, got %v", ErrDraftPending, err)
+	}
+
+	if len(game.Players[0].Influences) != 0 || len(game.Deck) != 5 {
+		t.Fatalf("nobody should be dealt a card before drafting")
+	}
+
+	game, _ = mustApply(t, game, DraftInfluence{PlayerID: "p0", Role: "Contessa"})
+	if len(game.Players[0].Influences) != 1 || len(game.Deck) != 9 {
+		t.Fatalf("the undrafted cards should go back to the deck, got %d cards", len(game.Deck))
+	}
+	game, _ = mustApply(t, game, DraftInfluence{PlayerID: "p1", Role: "Duke"})
+
+	if game.Players[0].Influences[0].Role != "Contessa" || game.Players[1].Influences[0].Role != "Duke" {
+		t.Fatalf("drafted roles should join the hands")
+	}
+	if len(game.Players[0].Influences) != 2 || len(game.Players[1].Influences) != 2 || len(game.Deck) != 11 {
+		t.Fatalf("every player should be dealt a random card once the drafts are in, deck has %d cards", len(game.Deck))
+	}
+	mustApply(t, game, DeclareAction{PlayerID: game.currentPlayer().ID, ActionName: "income"})
+}
+
+func TestDuelDraftsAlwaysOfferEveryRole(t *testing.T) {
+	settings := DefaultSettings()
+	settings.MaxPlayers, settings.Duel = 2, true
+
+	for seed := int64(0); seed < 500; seed++ {
+		lobby := NewGame("game", "CODE", NewPlayer("p0", "player 0"), settings, time.Now())
+		lobby.Players = append(lobby.Players, NewPlayer("p1", "player 1"))
+
+		game, _ := mustApply(t, lobby, StartGame{PlayerID: "p0", Seed: seed})
+		for _, draft := range game.PendingDrafts {
+			if len(draft.Options) != 5 {
+				t.Fatalf("seed %d offered %s only %d options", seed, draft.PlayerID, len(draft.Options))
+			}
+		}
+	}
+}
+
+func TestRematchDealsANewGameToTheSameRoom(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Captain"}, []string{"Contessa"}, []string{"Assassin", "Ambassador"})
+
+	game, events := mustApply(t, game, LeaveGame{PlayerID: "p2"})
+	if events[len(events)-1].Type != "player_left" {
+		t.Fatalf("expected player_left, got %s", events[len(events)-1].Type)
+	}
+	game, _ = mustApply(t, game, Forfeit{PlayerID: "p1"})
+	if !game.Finished || game.Players[2].Alive || !game.Players[2].Left {
+		t.Fatalf("p2 should have forfeited by leaving, and p0 won")
+	}
+
+	_, _, err := Apply(game, Rematch{PlayerID: "p1", Seed: 1})
+	if !errors.Is(err, ErrOnlyAdminCanRematch) {
+		t.Fatalf("expected %v, got %v", ErrOnlyAdminCanRematch, err)
+	}
+
+	game, events = mustApply(t, game, Rematch{PlayerID: "p0", Seed: 1, WinnerStarts: true})
+	if game.Finished || !game.Started || len(game.Players) != 2 {
+		t.Fatalf("the rematch should start with the players who stayed")
+	}
+	if game.currentPlayer().ID != "p0" {
+		t.Fatalf("the last winner should play first")
+	}
+	for _, player := range game.Players {
+		if !player.Alive || len(player.Influences) != 2 || player.Coins != 2 {
+			t.Fatalf("%s should be dealt a fresh hand", player.ID)
+		}
+	}
+	if events[1].Type != "rematch_started" {
+		t.Fatalf("expected rematch_started, got %s", events[1].Type)
+	}
+}
+
+func TestSeriesDealsGamesUntilSomeoneReachesTheWinTarget(t *testing.T) {
+	game := newTestGame([]string{"Duke", "Captain"}, []string{"Contessa", "Assassin"})
+	game.Settings.SeriesWinTarget = 2
+
+	game, _ = mustApply(t, game, Forfeit{PlayerID: "p1"})
+	if !game.SeriesContinues() || game.SeriesScores["p0"] != 1 {
+		t.Fatalf("the first win should count towards a series still in progress")
+	}
+
+	game, events := mustApply(t, game, NextSeriesGame{Seed: 1})
+	if !game.Started || game.Finished || game.SeriesScores["p0"] != 1 {
+		t.Fatalf("the next game should start and keep the series score")
+	}
+	if events[0].Type != "series_game_started" {
+		t.Fatalf("expected series_game_started, got %s", events[0].Type)
+	}
+
+	game, events = mustApply(t, game, Forfeit{PlayerID: "p1"})
+	if !game.SeriesFinished || game.SeriesContinues() {
+		t.Fatalf("p0 should have won the series")
+	}
+	last := events[len(events)-1]
+	if last.Type != "series_finished" {
+		t.Fatalf("expected series_finished, got %s", last.Type)
+	}
+	standings := last.Payload["standings"].([]SeriesStanding)
+	if standings[0].PlayerID != "p0" || standings[0].Wins != 2 || standings[1].Wins != 0 {
+		t.Fatalf("unexpected standings %+v", standings)
+	}
+
+	_, _, err := Apply(game, NextSeriesGame{Seed: 2})
+	if !errors.Is(err, ErrNoSeriesInProgress) {
+		t.Fatalf("expected %v, got %v", ErrNoSeriesInProgress, err)
+	}
+
+	game, _ = mustApply(t, game, Rematch{PlayerID: "p0", Seed: 2})
+	if game.SeriesFinished || len(game.SeriesScores) != 0 {
+		t.Fatalf("a rematch after the series should start a new one")
+	}
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoPendingExchange    = errors.New("no_pending_exchange")
+	ErrExchangePending      = errors.New("exchange_pending")
+	ErrInvalidExchangeCount = errors.New("invalid_exchange_count")
+	ErrInvalidExchangeRole  = errors.New("invalid_exchange_role")
+)
+
+// PendingExchange holds the cards an Ambassador or Inquisitor drew while the player decides which ones to keep.
+type PendingExchange struct {
+	PlayerID string      `json:"playerId"`
+	Drawn    []Influence `json:"drawn"`
+}
+
+// startExchange draws up to drawCount cards from the deck for playerID.
+func (game *Game) startExchange(playerID string, drawCount int) {
+	drawCount = min(drawCount, len(game.Deck))
+
+	drawn := make([]Influence, 0, drawCount)
+	drawn = append(drawn, game.Deck[:drawCount]...)
+	game.Deck = game.Deck[drawCount:]
+
+	game.PendingExchange = &PendingExchange{
+		PlayerID: playerID,
+		Drawn:    drawn,
+	}
+}
+
+// exchangeCards completes the exchange of the player, keeping the given roles.
+func (game *Game) exchangeCards(command ExchangeCards) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	if err := game.completeExchange(command.PlayerID, command.Keep); err != nil {
+		return nil, err
+	}
+
+	return []Event{
+		{
+			Type: "exchange_completed",
+			Payload: map[string]any{
+				"playerID": command.PlayerID,
+			},
+		},
+	}, nil
+}
+
+// exchangeOptions returns every role the player can keep: their face-down influences plus the drawn cards.
+func (game *Game) exchangeOptions() []string {
+	player := game.findPlayer(game.PendingExchange.PlayerID)
+
+	options := make([]string, 0, len(player.Influences)+len(game.PendingExchange.Drawn))
+	for _, influence := range player.Influences {
+		if !influence.Revealed {
+			options = append(options, influence.Role)
+		}
+	}
+	for _, influence := range game.PendingExchange.Drawn {
+		options = append(options, influence.Role)
+	}
+
+	return options
+}
+
+// completeExchange keeps the chosen roles in the player's hand, as many as they had face down,
+// shuffles the rest back into the deck and ends the turn.
+func (game *Game) completeExchange(playerID string, keep []string) error {
+	if game.PendingExchange == nil || game.PendingExchange.PlayerID != playerID {
+		return ErrNoPendingExchange
+	}
+
+	if err := game.ensureNoInfluenceLossPending(); err != nil {
+		return err
+	}
+
+	player := game.findPlayer(playerID)
+
+	faceDownCount := 0
+	for _, influence := range player.Influences {
+		if !influence.Revealed {
+			faceDownCount++
+		}
+	}
+	if len(keep) != faceDownCount {
+		return ErrInvalidExchangeCount
+	}
+
+	remaining := game.exchangeOptions()
+	for _, role := range keep {
+		index := -1
+		for i, option := range remaining {
+			if option == role {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return ErrInvalidExchangeRole
+		}
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+
+	influences := make([]Influence, 0, len(player.Influences))
+	for _, influence := range player.Influences {
+		if influence.Revealed {
+			influences = append(influences, influence)
+		}
+	}
+	for _, role := range keep {
+		influences = append(influences, Influence{Role: role})
+	}
+	player.Influences = influences
+
+	for _, role := range remaining {
+		game.Deck = append(game.Deck, Influence{Role: role})
+	}
+	game.shuffleDeck()
+
+	game.PendingExchange = nil
+	game.advanceTurn()
+
+	return nil
+}
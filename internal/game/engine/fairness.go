@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+/*
+openingDeal draws the starting player and the shuffled deck of a game seeded with seed,
+before any card is dealt. Outside a duel, players are dealt two cards each from the top
+of the deck, in seat order.
+
+⚠️ Warning:
+- startGame and VerifyDeal must both go through here, otherwise honest deals stop verifying
+*/
+func openingDeal(seed int64, playerCount int, variant string) (startingTurnIndex int, deck []Influence, randomDraws uint64, err error) {
+	draws := &Game{Seed: seed}
+
+	startingTurnIndex = draws.random().IntN(playerCount)
+
+	deck, err = NewDeck(playerCount, variant)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	shuffleInfluences(draws.random(), deck)
+
+	return startingTurnIndex, deck, draws.RandomDraws, nil
+}
+
+// revealedSeed is the seed as published once the game is over, as a string so clients do not lose precision.
+func (game *Game) revealedSeed() string {
+	return strconv.FormatInt(game.Seed, 10)
+}
+
+// DeckCommitment is the hex SHA-256 of the seed and the order of the shuffled deck.
+func DeckCommitment(seed int64, deck []Influence) string {
+	roles := make([]string, 0, len(deck))
+	for _, influence := range deck {
+		roles = append(roles, influence.Role)
+	}
+
+	sum := sha256.Sum256([]byte(strconv.FormatInt(seed, 10) + ":" + strings.Join(roles, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+VerifyDeal checks a revealed seed against the commitment published when the game started.
+
+It rebuilds the deck of the variant for playerCount players, shuffles it with the seed the
+way the server does and compares the result with the commitment. Since every later shuffle
+is drawn from the same seed, a matching seed also lets clients replay every draw of the game.
+*/
+func VerifyDeal(commitment string, seed int64, playerCount int, variant string) bool {
+	_, deck, _, err := openingDeal(seed, playerCount, variant)
+	if err != nil {
+		return false
+	}
+	return DeckCommitment(seed, deck) == commitment
+}
@@ -0,0 +1,84 @@
+package engine
+
+/*
+forfeit takes playerID out of a game in progress.
+
+Every influence of the player is revealed where it lies, so nothing goes back to the
+deck, and the player is marked dead. Whatever the game was waiting on them for is
+dropped: the pending action they are part of is canceled, their prompts are removed and
+the cards drawn for their exchange go back to the deck, and an examination they are part
+of ends. The turn moves on if it was theirs, or if the action of the current turn got canceled.
+*/
+func (game *Game) forfeit(command Forfeit) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	player := game.findPlayer(command.PlayerID)
+	if player == nil {
+		return nil, ErrPlayerNotFound
+	}
+	if !player.Alive {
+		return nil, ErrPlayerIsDead
+	}
+
+	events := []Event{}
+	endTurn := game.currentPlayer().ID == player.ID
+
+	if game.PendingAction != nil && game.PendingAction.involves(player.ID) {
+		pendingAction := game.PendingAction
+		pendingAction.Status = PendingActionCanceled
+		game.PendingAction = nil
+		endTurn = true
+
+		events = append(events, closedActionEvent(pendingAction))
+	}
+
+	prompts := make([]InfluenceLossPrompt, 0, len(game.PendingInfluenceLosses))
+	for _, prompt := range game.PendingInfluenceLosses {
+		if prompt.PlayerID != player.ID {
+			prompts = append(prompts, prompt)
+		}
+	}
+	game.PendingInfluenceLosses = prompts
+
+	if game.PendingExchange != nil && game.PendingExchange.PlayerID == player.ID {
+		game.Deck = append(game.Deck, game.PendingExchange.Drawn...)
+		game.shuffleDeck()
+		game.PendingExchange = nil
+	}
+
+	if examination := game.PendingExamination; examination != nil && (examination.ActorID == player.ID || examination.TargetID == player.ID) {
+		game.PendingExamination = nil
+		endTurn = true
+	}
+
+	for i := range player.Influences {
+		player.Influences[i].Revealed = true
+	}
+
+	events = append(events, Event{
+		Type: "player_forfeited",
+		Payload: map[string]any{
+			"playerID":   player.ID,
+			"influences": player.Influences,
+		},
+	})
+
+	game.eliminateIfOut(player)
+	if game.Finished {
+		return events, nil
+	}
+
+	if endTurn {
+		game.advanceTurn()
+		return events, nil
+	}
+
+	// The player may have been the last one the pending action was waiting on.
+	if len(game.PendingInfluenceLosses) == 0 {
+		events = append(events, game.closeIfEveryonePassed()...)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,124 @@
+package engine
+
+import "errors"
+
+var (
+	ErrInfluenceLossPending   = errors.New("influence_loss_pending")
+	ErrNoPendingInfluenceLoss = errors.New("no_pending_influence_loss")
+	ErrInvalidInfluenceIndex  = errors.New("invalid_influence_index")
+)
+
+const (
+	InfluenceLossChallenge     = "challenge"
+	InfluenceLossAssassination = "assassination"
+	InfluenceLossCoup          = "coup"
+)
+
+// InfluenceLossPrompt asks a player to pick which face-down influence to reveal.
+type InfluenceLossPrompt struct {
+	PlayerID string `json:"playerId"`
+	Reason   string `json:"reason"` // "challenge", "assassination", "coup"
+}
+
+/*
+loseInfluence makes playerID lose an influence.
+
+A player with a single face-down card loses it right away; a player with more
+has to choose, so a prompt is queued and the game waits for the answer.
+*/
+func (game *Game) loseInfluence(playerID string, reason string) {
+	game.PendingInfluenceLosses = append(game.PendingInfluenceLosses, InfluenceLossPrompt{
+		PlayerID: playerID,
+		Reason:   reason,
+	})
+	game.settleInfluenceLosses()
+}
+
+// settleInfluenceLosses resolves queued prompts that leave the player no choice.
+func (game *Game) settleInfluenceLosses() {
+	for len(game.PendingInfluenceLosses) > 0 {
+		prompt := game.PendingInfluenceLosses[0]
+		player := game.findPlayer(prompt.PlayerID)
+
+		faceDownIndexes := player.faceDownIndexes()
+		if len(faceDownIndexes) > 1 {
+			return
+		}
+
+		game.PendingInfluenceLosses = game.PendingInfluenceLosses[1:]
+		if len(faceDownIndexes) == 1 {
+			game.turnFaceUp(player, faceDownIndexes[0])
+		}
+	}
+}
+
+// answerInfluenceLoss reveals the influence the player picked for their pending prompt.
+func (game *Game) answerInfluenceLoss(command RevealInfluence) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	answeredPrompt, err := game.revealInfluence(command.PlayerID, command.InfluenceIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response window was on hold while the prompts were open, it gets its full time back.
+	if len(game.PendingInfluenceLosses) == 0 && game.PendingAction != nil {
+		game.startResponseWindow(game.PendingAction, command.Now)
+	}
+
+	return []Event{
+		{
+			Type: "influence_lost",
+			Payload: map[string]any{
+				"playerID":  command.PlayerID,
+				"reason":    answeredPrompt.Reason,
+				"influence": game.findPlayer(command.PlayerID).Influences[command.InfluenceIndex],
+			},
+		},
+	}, nil
+}
+
+// revealInfluence answers the first queued prompt, which must belong to playerID.
+func (game *Game) revealInfluence(playerID string, influenceIndex int) (*InfluenceLossPrompt, error) {
+	if len(game.PendingInfluenceLosses) == 0 || game.PendingInfluenceLosses[0].PlayerID != playerID {
+		return nil, ErrNoPendingInfluenceLoss
+	}
+
+	player := game.findPlayer(playerID)
+	if influenceIndex < 0 || influenceIndex >= len(player.Influences) || player.Influences[influenceIndex].Revealed {
+		return nil, ErrInvalidInfluenceIndex
+	}
+
+	prompt := game.PendingInfluenceLosses[0]
+
+	game.PendingInfluenceLosses = game.PendingInfluenceLosses[1:]
+	game.turnFaceUp(player, influenceIndex)
+	game.settleInfluenceLosses()
+
+	return &prompt, nil
+}
+
+// turnFaceUp reveals an influence and eliminates the player if it was their last one.
+func (game *Game) turnFaceUp(player *Player, influenceIndex int) {
+	player.Influences[influenceIndex].Revealed = true
+	game.eliminateIfOut(player)
+}
+
+func (game *Game) ensureNoInfluenceLossPending() error {
+	if len(game.PendingInfluenceLosses) > 0 {
+		return ErrInfluenceLossPending
+	}
+	return nil
+}
+
+func (player *Player) faceDownIndexes() []int {
+	indexes := []int{}
+	for i, influence := range player.Influences {
+		if !influence.Revealed {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
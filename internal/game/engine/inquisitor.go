@@ -0,0 +1,115 @@
+package engine
+
+import "errors"
+
+var (
+	ErrNoPendingExamination = errors.New("no_pending_examination")
+	ErrExaminationPending   = errors.New("examination_pending")
+	ErrInfluenceNotShown    = errors.New("influence_not_shown_yet")
+)
+
+/*
+PendingExamination is the card of the target an Inquisitor is looking at, while they
+decide whether the target keeps it or has to exchange it.
+
+The target picks which card to show first, so InfluenceIndex only means something once Shown is set.
+
+⚠️ Warning:
+- only the actor may learn the examined role, it must never be part of the public state
+*/
+type PendingExamination struct {
+	ActorID        string `json:"actorId"`
+	TargetID       string `json:"targetId"`
+	InfluenceIndex int    `json:"influenceIndex"`
+	Shown          bool   `json:"shown"`
+}
+
+// startExamination asks the target to show one of their face-down cards to the actor.
+// A target with a single face-down card has no choice, so it is shown right away.
+func (game *Game) startExamination(actorID string, targetID string) {
+	faceDownIndexes := game.findPlayer(targetID).faceDownIndexes()
+	if len(faceDownIndexes) == 0 {
+		return
+	}
+
+	game.PendingExamination = &PendingExamination{
+		ActorID:  actorID,
+		TargetID: targetID,
+	}
+	if len(faceDownIndexes) == 1 {
+		game.PendingExamination.InfluenceIndex = faceDownIndexes[0]
+		game.PendingExamination.Shown = true
+	}
+}
+
+// showInfluence lets the examined target pick which of their face-down cards the Inquisitor looks at.
+func (game *Game) showInfluence(command ShowInfluence) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	examination := game.PendingExamination
+	if examination == nil || examination.Shown || examination.TargetID != command.PlayerID {
+		return nil, ErrNoPendingExamination
+	}
+
+	if err := game.ensureNoInfluenceLossPending(); err != nil {
+		return nil, err
+	}
+
+	target := game.findPlayer(command.PlayerID)
+	if command.InfluenceIndex < 0 || command.InfluenceIndex >= len(target.Influences) || target.Influences[command.InfluenceIndex].Revealed {
+		return nil, ErrInvalidInfluenceIndex
+	}
+
+	examination.InfluenceIndex = command.InfluenceIndex
+	examination.Shown = true
+
+	return []Event{}, nil
+}
+
+// decideExamination lets the Inquisitor return the examined card, or force the target
+// to shuffle it into the deck and draw a new one. Either way the turn ends.
+func (game *Game) decideExamination(command DecideExamination) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	examination := game.PendingExamination
+	if examination == nil || examination.ActorID != command.PlayerID {
+		return nil, ErrNoPendingExamination
+	}
+
+	if err := game.ensureNoInfluenceLossPending(); err != nil {
+		return nil, err
+	}
+
+	// The target may have lost their last face-down card since, losing a challenge against the examination.
+	target := game.findPlayer(examination.TargetID)
+	if !examination.Shown {
+		if target.hasUnrevealedInfluence() {
+			return nil, ErrInfluenceNotShown
+		}
+		command.ForceExchange = false
+	}
+
+	examined := target.Influences[examination.InfluenceIndex]
+	if command.ForceExchange && !examined.Revealed {
+		game.replaceInfluence(target, examined.Role)
+	}
+
+	game.PendingExamination = nil
+	game.advanceTurn()
+
+	return []Event{
+		{
+			Type: "examination_completed",
+			Payload: map[string]any{
+				"actorID":        examination.ActorID,
+				"targetID":       examination.TargetID,
+				"influenceIndex": examination.InfluenceIndex,
+				"forcedExchange": command.ForceExchange,
+			},
+		},
+	}, nil
+}
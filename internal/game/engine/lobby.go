@@ -0,0 +1,246 @@
+package engine
+
+import "time"
+
+func NewPlayer(playerID string, nickname string) *Player {
+	return &Player{
+		ID:         playerID,
+		Nickname:   nickname,
+		Coins:      2,
+		Alive:      true,
+		Influences: []Influence{},
+	}
+}
+
+func NewGame(gameID string, joinCode string, adminPlayer *Player, settings Settings, createdAt time.Time) *Game {
+	return &Game{
+		ID:        gameID,
+		CreatedAt: createdAt,
+		Players:   []*Player{adminPlayer},
+		JoinCode:  joinCode,
+		AdminID:   adminPlayer.ID,
+		TurnIndex: 0,
+		Started:   false,
+		Finished:  false,
+		Settings:  settings,
+		Deck:      []Influence{},
+	}
+}
+
+func (game *Game) joinGame(command JoinGame) ([]Event, error) {
+	if game.Started {
+		return nil, ErrAlreadyStarted
+	}
+
+	if len(game.Players) >= game.Settings.MaxPlayers {
+		return nil, ErrRoomFull
+	}
+
+	for _, p := range game.Players {
+		if p.Nickname == command.Nickname {
+			return nil, ErrPlayerAlreadyJoined
+		}
+	}
+
+	joinedPlayer := NewPlayer(command.PlayerID, command.Nickname)
+	game.Players = append(game.Players, joinedPlayer)
+
+	return []Event{
+		{
+			Type: "player_joined",
+			Payload: map[string]any{
+				"newPlayer": joinedPlayer,
+			},
+		},
+	}, nil
+}
+
+/*
+leaveGame takes playerID out of the lobby.
+
+Once the game has started the player keeps their seat until a rematch, marked as gone;
+leaving a game in progress forfeits it, unless the player is already out.
+*/
+func (game *Game) leaveGame(command LeaveGame) ([]Event, error) {
+	player := game.findPlayer(command.PlayerID)
+	if player == nil {
+		return nil, ErrPlayerNotFound
+	}
+
+	if !game.Started {
+		return game.removePlayer(command.PlayerID, false), nil
+	}
+
+	events := []Event{}
+	if !game.Finished && player.Alive {
+		forfeitEvents, err := game.forfeit(Forfeit{PlayerID: command.PlayerID})
+		if err != nil {
+			return nil, err
+		}
+		events = forfeitEvents
+	}
+
+	player.Left = true
+	game.handOverAdmin(player.ID)
+
+	return append(events, Event{
+		Type: "player_left",
+		Payload: map[string]any{
+			"playerID": player.ID,
+			"kicked":   false,
+		},
+	}), nil
+}
+
+// kickPlayer lets the admin take another player out of a game that has not started yet.
+func (game *Game) kickPlayer(command KickPlayer) ([]Event, error) {
+	if game.Started {
+		return nil, ErrAlreadyStarted
+	}
+
+	if game.AdminID != command.PlayerID {
+		return nil, ErrOnlyAdminCanKick
+	}
+	if command.TargetID == command.PlayerID {
+		return nil, ErrCannotTargetSelf
+	}
+	if game.findPlayer(command.TargetID) == nil {
+		return nil, ErrTargetPlayerNotFound
+	}
+
+	return game.removePlayer(command.TargetID, true), nil
+}
+
+/*
+removePlayer drops playerID from the room, whether it is a lobby or a finished game being dealt again.
+
+The admin role is handed over as in handOverAdmin.
+*/
+func (game *Game) removePlayer(playerID string, kicked bool) []Event {
+	remaining := make([]*Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		if player.ID != playerID {
+			remaining = append(remaining, player)
+		}
+	}
+	game.Players = remaining
+	game.handOverAdmin(playerID)
+
+	return []Event{
+		{
+			Type: "player_left",
+			Payload: map[string]any{
+				"playerID": playerID,
+				"kicked":   kicked,
+			},
+		},
+	}
+}
+
+/*
+handOverAdmin makes the oldest player still in the room the admin when leavingID was the
+admin. When nobody is left, AdminID is emptied and the room is up for deletion.
+*/
+func (game *Game) handOverAdmin(leavingID string) {
+	if game.AdminID != leavingID {
+		return
+	}
+
+	game.AdminID = ""
+	for _, player := range game.Players {
+		if player.ID != leavingID && !player.Left {
+			game.AdminID = player.ID
+			return
+		}
+	}
+}
+
+func (game *Game) startGame(command StartGame) ([]Event, error) {
+	if game.Started {
+		return nil, ErrAlreadyStarted
+	}
+	if game.Finished {
+		return nil, ErrGameAlreadyFinished
+	}
+
+	if game.AdminID != command.PlayerID {
+		return nil, ErrOnlyAdminCanStartGame
+	}
+
+	if len(game.Players) < MinPlayers {
+		return nil, ErrNeedAtLeastTwoPlayers
+	}
+	if len(game.Players) > game.Settings.MaxPlayers {
+		return nil, ErrTooManyPlayers
+	}
+	if game.Settings.Duel && len(game.Players) != 2 {
+		return nil, ErrDuelNeedsTwoPlayers
+	}
+
+	startingTurnIndex, deck, randomDraws, err := openingDeal(command.Seed, len(game.Players), game.Settings.DeckVariant)
+	if err != nil {
+		return nil, err
+	}
+
+	if command.FirstPlayerID != "" {
+		for i, player := range game.Players {
+			if player.ID == command.FirstPlayerID {
+				startingTurnIndex = i
+			}
+		}
+	}
+
+	game.Started = true
+	game.Seed = command.Seed
+	game.RandomDraws = randomDraws
+	game.DeckCommitment = DeckCommitment(command.Seed, deck)
+	game.startTurns(startingTurnIndex)
+
+	neededCards := len(game.Players) * 2
+	if neededCards > len(deck) {
+		return nil, ErrNotEnoughInfluences
+	}
+
+	dealtCoins := 0
+	for i, p := range game.Players {
+		p.Coins = game.Settings.StartingCoins
+		if game.Settings.Duel && i == startingTurnIndex {
+			p.Coins = DuelStartingPlayerCoins
+		}
+		p.Alive = true
+		dealtCoins += p.Coins
+	}
+
+	if dealtCoins > TreasurySupply {
+		return nil, ErrTreasuryTooSmall
+	}
+	game.Treasury = TreasurySupply - dealtCoins
+
+	if game.Settings.Duel {
+		deck = game.dealDuel(deck)
+	} else {
+		for _, p := range game.Players {
+			p.Influences = make([]Influence, 0, 2)
+
+			p.Influences = append(p.Influences, deck[0], deck[1])
+
+			deck = deck[2:]
+		}
+	}
+
+	game.Deck = deck
+	game.TreasuryReserve = 0
+
+	if game.Settings.Ruleset == RulesetReformation {
+		game.assignAllegiances()
+	}
+
+	return []Event{
+		{
+			Type: "game_started",
+			Payload: map[string]any{
+				"deckCommitment": game.DeckCommitment,
+			},
+		},
+	}, nil
+}
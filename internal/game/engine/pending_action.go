@@ -0,0 +1,303 @@
+package engine
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	PendingActionDeclared = "declared"
+	PendingActionBlocked  = "blocked"
+	PendingActionResolved = "resolved"
+	PendingActionCanceled = "canceled"
+)
+
+// PendingAction is the response window opened by an action that other players can react to.
+type PendingAction struct {
+	ID        string     `json:"id"`
+	ActorID   string     `json:"actorId"`
+	Action    ActionType `json:"action"`
+	TargetID  *string    `json:"targetId,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Status    string     `json:"status"` // "declared", "blocked", "resolved", "canceled"
+	PassedIDs []string   `json:"passedIds"`
+	BlockerID *string    `json:"blockerId,omitempty"`
+	BlockRole string     `json:"blockRole,omitempty"`
+
+	// ActionChallenged is set once the actor proved the claim; only blockers may still respond.
+	ActionChallenged bool `json:"actionChallenged"`
+
+	// StolenCoins is only set once a steal resolves, since the target may hold less than 2 coins.
+	StolenCoins int `json:"stolenCoins,omitempty"`
+}
+
+// passAction records that the player lets the pending action (or its block) go through.
+// Once every eligible player has passed, the action resolves, or is canceled if it was blocked.
+func (game *Game) passAction(command PassAction) ([]Event, error) {
+	pendingAction, err := game.respondablePendingAction(command.ActionID, command.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pendingAction.hasPassed(command.PlayerID) {
+		return nil, ErrAlreadyResponded
+	}
+	pendingAction.PassedIDs = append(pendingAction.PassedIDs, command.PlayerID)
+
+	events := []Event{
+		{
+			Type: "action_passed",
+			Payload: map[string]any{
+				"actionID": command.ActionID,
+				"playerID": command.PlayerID,
+			},
+		},
+	}
+
+	return append(events, game.closeIfEveryonePassed()...), nil
+}
+
+// closeIfEveryonePassed resolves the pending action, or cancels it if it was blocked,
+// once no eligible player is left to respond.
+func (game *Game) closeIfEveryonePassed() []Event {
+	if game.PendingAction == nil || !game.allEligiblePlayersPassed() {
+		return nil
+	}
+
+	// Nobody challenged the block, so it stands and the action is canceled.
+	if game.PendingAction.Status == PendingActionBlocked {
+		return []Event{closedActionEvent(game.cancelPendingAction())}
+	}
+	return []Event{closedActionEvent(game.resolvePendingAction())}
+}
+
+// blockAction lets the player block the pending action by claiming one of the roles that counter it.
+func (game *Game) blockAction(command BlockAction) ([]Event, error) {
+	pendingAction, err := game.respondablePendingAction(command.ActionID, command.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocking the untargeted actions of your own faction is off limits too.
+	if pendingAction.TargetID == nil && game.sameFaction(command.PlayerID, pendingAction.ActorID) {
+		return nil, ErrCannotTargetOwnFaction
+	}
+
+	if err := pendingAction.block(command.PlayerID, command.Role); err != nil {
+		return nil, err
+	}
+	// The other players get a fresh window to challenge the block.
+	game.startResponseWindow(pendingAction, command.Now)
+
+	return []Event{
+		{
+			Type: "action_blocked",
+			Payload: map[string]any{
+				"pendingAction": pendingAction,
+			},
+		},
+	}, nil
+}
+
+func closedActionEvent(pendingAction *PendingAction) Event {
+	eventType := "action_resolved"
+	if pendingAction.Status == PendingActionCanceled {
+		eventType = "action_canceled"
+	}
+
+	return Event{
+		Type: eventType,
+		Payload: map[string]any{
+			"pendingAction": pendingAction,
+		},
+	}
+}
+
+// openPendingAction opens the response window of action at createdAt.
+// IDs are numbered, so replaying the same commands gives the same IDs.
+func (game *Game) openPendingAction(actorID string, action ActionType, createdAt time.Time) *PendingAction {
+	game.ActionSequence++
+
+	game.PendingAction = &PendingAction{
+		ID:        "action-" + strconv.Itoa(game.ActionSequence),
+		ActorID:   actorID,
+		Action:    action,
+		TargetID:  action.TargetPlayerID,
+		CreatedAt: createdAt,
+		Status:    PendingActionDeclared,
+		PassedIDs: []string{},
+	}
+	game.startResponseWindow(game.PendingAction, createdAt)
+
+	return game.PendingAction
+}
+
+// respondablePendingAction returns the pending action playerID is allowed to respond to.
+func (game *Game) respondablePendingAction(actionID string, playerID string) (*PendingAction, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+
+	if err := game.ensureNoInfluenceLossPending(); err != nil {
+		return nil, err
+	}
+
+	pendingAction := game.PendingAction
+	if pendingAction == nil || pendingAction.ID != actionID {
+		return nil, ErrPendingActionNotFound
+	}
+
+	if pendingAction.respondingPlayerExcluded() == playerID {
+		return nil, ErrCannotRespondToSelf
+	}
+
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return nil, ErrPlayerNotFound
+	}
+	if !player.Alive || !player.hasUnrevealedInfluence() {
+		return nil, ErrPlayerIsDead
+	}
+
+	return pendingAction, nil
+}
+
+// respondingPlayerExcluded returns the player whose claim is currently open and
+// therefore cannot respond to it: the actor, or the blocker once the action is blocked.
+func (pendingAction *PendingAction) respondingPlayerExcluded() string {
+	claimantID, _, _ := pendingAction.openClaim()
+	return claimantID
+}
+
+// openClaim returns the player and role that can currently be challenged, and whether
+// the claim is that the player does not hold the role.
+func (pendingAction *PendingAction) openClaim() (string, string, bool) {
+	if pendingAction.Status == PendingActionBlocked {
+		return *pendingAction.BlockerID, pendingAction.BlockRole, false
+	}
+	if pendingAction.Action.DeniedRole != "" {
+		return pendingAction.ActorID, pendingAction.Action.DeniedRole, true
+	}
+	return pendingAction.ActorID, pendingAction.Action.ClaimedRole, false
+}
+
+func (pendingAction *PendingAction) block(blockerID string, role string) error {
+	if !pendingAction.Action.IsBlockable {
+		return ErrActionNotBlockable
+	}
+
+	if pendingAction.Status != PendingActionDeclared {
+		return ErrActionAlreadyBlocked
+	}
+
+	if !pendingAction.canBlock(blockerID) {
+		return ErrOnlyTargetCanBlock
+	}
+
+	canBlock := false
+	for _, blockableRole := range pendingAction.Action.BlockableRoles {
+		if blockableRole.Role == role {
+			canBlock = true
+			break
+		}
+	}
+	if !canBlock {
+		return ErrInvalidBlockRole
+	}
+
+	pendingAction.Status = PendingActionBlocked
+	pendingAction.BlockerID = &blockerID
+	pendingAction.BlockRole = role
+	pendingAction.PassedIDs = []string{}
+
+	return nil
+}
+
+// canBlock reports whether playerID may block: the target of a targeted action, anyone otherwise.
+func (pendingAction *PendingAction) canBlock(playerID string) bool {
+	return pendingAction.TargetID == nil || *pendingAction.TargetID == playerID
+}
+
+// involves reports whether playerID is the actor, the target or the blocker of the pending action.
+func (pendingAction *PendingAction) involves(playerID string) bool {
+	if pendingAction.ActorID == playerID {
+		return true
+	}
+	if pendingAction.TargetID != nil && *pendingAction.TargetID == playerID {
+		return true
+	}
+	return pendingAction.BlockerID != nil && *pendingAction.BlockerID == playerID
+}
+
+func (pendingAction *PendingAction) hasPassed(playerID string) bool {
+	for _, passedID := range pendingAction.PassedIDs {
+		if passedID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func (game *Game) allEligiblePlayersPassed() bool {
+	excludedID := game.PendingAction.respondingPlayerExcluded()
+
+	for _, player := range game.Players {
+		if !player.Alive || !player.hasUnrevealedInfluence() || player.ID == excludedID {
+			continue
+		}
+		if game.PendingAction.Status == PendingActionDeclared && game.PendingAction.ActionChallenged && !game.PendingAction.canBlock(player.ID) {
+			continue
+		}
+		if !game.PendingAction.hasPassed(player.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvePendingAction applies the effect of the pending action and ends the turn.
+func (game *Game) resolvePendingAction() *PendingAction {
+	pendingAction := game.PendingAction
+	actor := game.findPlayer(pendingAction.ActorID)
+
+	switch pendingAction.Action.Name {
+	case "foreign_aid":
+		game.takeFromTreasury(actor, 2)
+	case "tax":
+		game.takeFromTreasury(actor, 3)
+	case "assassinate":
+		game.loseInfluence(*pendingAction.TargetID, InfluenceLossAssassination)
+	case "steal":
+		target := game.findPlayer(*pendingAction.TargetID)
+		pendingAction.StolenCoins = transferCoins(target, actor, 2)
+	case "exchange":
+		game.startExchange(actor.ID, pendingAction.Action.Draw)
+	case "examine":
+		game.startExamination(actor.ID, *pendingAction.TargetID)
+	case "embezzle":
+		actor.Coins += game.TreasuryReserve
+		game.TreasuryReserve = 0
+	}
+
+	pendingAction.Status = PendingActionResolved
+	game.PendingAction = nil
+
+	// An exchange or an examination keeps the turn until the player makes up their mind.
+	if game.PendingExchange == nil && game.PendingExamination == nil {
+		game.advanceTurn()
+	}
+
+	return pendingAction
+}
+
+// cancelPendingAction drops the pending action without applying it and ends the turn.
+func (game *Game) cancelPendingAction() *PendingAction {
+	pendingAction := game.PendingAction
+
+	pendingAction.Status = PendingActionCanceled
+	game.PendingAction = nil
+	game.advanceTurn()
+
+	return pendingAction
+}
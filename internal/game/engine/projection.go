@@ -0,0 +1,165 @@
+package engine
+
+const (
+	ViewerPlayer    = "player"
+	ViewerSpectator = "spectator"
+	ViewerPostGame  = "post_game"
+)
+
+// Viewer is whoever a projected state is built for.
+type Viewer struct {
+	Kind     string // "player", "spectator", "post_game"
+	PlayerID string
+}
+
+/*
+ViewerFor picks how playerID gets to see the game: everything once it is over,
+their own hand while they are seated, public information only otherwise.
+*/
+func (game *Game) ViewerFor(playerID string) Viewer {
+	if game.Finished {
+		return Viewer{Kind: ViewerPostGame, PlayerID: playerID}
+	}
+
+	if game.findPlayer(playerID) != nil {
+		return Viewer{Kind: ViewerPlayer, PlayerID: playerID}
+	}
+
+	return Viewer{Kind: ViewerSpectator}
+}
+
+// Project builds the state viewer is allowed to see: face-down roles only to their owner, until the game is over.
+func (game *Game) Project(viewer Viewer) *PublicGameState {
+	playersPublicInfo := make([]PlayerPublicInfo, 0, len(game.Players))
+	for _, player := range game.Players {
+		playersPublicInfo = append(playersPublicInfo, projectPlayer(player, viewer))
+	}
+
+	state := &PublicGameState{
+		GameID:     game.ID,
+		JoinCode:   game.JoinCode,
+		Started:    game.Started,
+		Finished:   game.Finished,
+		WinnerID:   game.WinnerID,
+		TurnIndex:  game.TurnIndex,
+		TurnNumber: game.TurnNumber,
+		Round:      game.Round,
+		Players:    playersPublicInfo,
+		AdminID:    game.AdminID,
+		DeckLength: len(game.Deck),
+		Settings:   game.Settings,
+
+		Treasury:        game.Treasury,
+		TreasuryReserve: game.TreasuryReserve,
+		DeckCommitment:  game.DeckCommitment,
+
+		SeriesScores:   game.SeriesScores,
+		SeriesFinished: game.SeriesFinished,
+
+		PendingAction:          game.PendingAction,
+		PendingInfluenceLosses: game.PendingInfluenceLosses,
+	}
+
+	if game.Finished {
+		state.RevealedSeed = game.revealedSeed()
+	}
+
+	for _, draft := range game.PendingDrafts {
+		state.DraftingPlayerIDs = append(state.DraftingPlayerIDs, draft.PlayerID)
+	}
+
+	return state
+}
+
+func projectPlayer(player *Player, viewer Viewer) PlayerPublicInfo {
+	showHand := viewer.Kind == ViewerPostGame ||
+		(viewer.Kind == ViewerPlayer && viewer.PlayerID == player.ID)
+
+	influences := make([]PublicInfluence, 0, len(player.Influences))
+	for _, influence := range player.Influences {
+		if influence.Revealed || showHand {
+			influences = append(influences, PublicInfluence{
+				Role:     &influence.Role,
+				Revealed: influence.Revealed,
+			})
+		} else {
+			influences = append(influences, PublicInfluence{
+				Role:     nil,
+				Revealed: influence.Revealed,
+			})
+		}
+	}
+
+	return PlayerPublicInfo{
+		ID:         player.ID,
+		Nickname:   player.Nickname,
+		Coins:      player.Coins,
+		Alive:      player.Alive,
+		Influences: influences,
+		Allegiance: player.Allegiance,
+		Left:       player.Left,
+	}
+}
+
+// PrivateView is everything only playerID is allowed to know: their hand and what the game is waiting on them for.
+type PrivateView struct {
+	Player          *Player               `json:"player"`
+	InfluenceLosses []InfluenceLossPrompt `json:"influenceLosses"`
+	Exchange        *PendingExchange      `json:"exchange,omitempty"`
+	ExchangeOptions []string              `json:"exchangeOptions,omitempty"`
+	Examination     *ExaminedInfluence    `json:"examination,omitempty"`
+	ShowInfluenceTo string                `json:"showInfluenceTo,omitempty"` // the Inquisitor waiting for the player to pick a card
+	DraftOptions    []Influence           `json:"draftOptions,omitempty"`
+}
+
+// ExaminedInfluence is the card an Inquisitor is looking at.
+type ExaminedInfluence struct {
+	TargetID       string `json:"targetId"`
+	InfluenceIndex int    `json:"influenceIndex"`
+	Role           string `json:"role"`
+}
+
+// PrivateViewFor returns the private view of playerID, or ErrPlayerNotFound if they are not in the game.
+func (game *Game) PrivateViewFor(playerID string) (*PrivateView, error) {
+	player := game.findPlayer(playerID)
+	if player == nil {
+		return nil, ErrPlayerNotFound
+	}
+
+	view := &PrivateView{
+		Player:          player,
+		InfluenceLosses: []InfluenceLossPrompt{},
+	}
+
+	for _, prompt := range game.PendingInfluenceLosses {
+		if prompt.PlayerID == playerID {
+			view.InfluenceLosses = append(view.InfluenceLosses, prompt)
+		}
+	}
+
+	if game.PendingExchange != nil && game.PendingExchange.PlayerID == playerID {
+		view.Exchange = game.PendingExchange
+		view.ExchangeOptions = game.exchangeOptions()
+	}
+
+	if game.PendingExamination != nil && !game.PendingExamination.Shown && game.PendingExamination.TargetID == playerID {
+		view.ShowInfluenceTo = game.PendingExamination.ActorID
+	}
+
+	if game.PendingExamination != nil && game.PendingExamination.Shown && game.PendingExamination.ActorID == playerID {
+		examination := game.PendingExamination
+		view.Examination = &ExaminedInfluence{
+			TargetID:       examination.TargetID,
+			InfluenceIndex: examination.InfluenceIndex,
+			Role:           game.findPlayer(examination.TargetID).Influences[examination.InfluenceIndex].Role,
+		}
+	}
+
+	for _, draft := range game.PendingDrafts {
+		if draft.PlayerID == playerID {
+			view.DraftOptions = draft.Options
+		}
+	}
+
+	return view, nil
+}
@@ -0,0 +1,27 @@
+package engine
+
+import "math/rand/v2"
+
+/*
+random returns the source for the next random draw of the game.
+
+Each draw is seeded from the seed of the game and the number of draws made before it,
+so applying the same commands to a game with the same seed shuffles and deals exactly
+the same cards.
+*/
+func (game *Game) random() *rand.Rand {
+	source := rand.NewPCG(uint64(game.Seed), game.RandomDraws)
+	game.RandomDraws++
+	return rand.New(source)
+}
+
+// shuffleDeck shuffles the deck with the next random draw of the game.
+func (game *Game) shuffleDeck() {
+	shuffleInfluences(game.random(), game.Deck)
+}
+
+func shuffleInfluences(random *rand.Rand, influences []Influence) {
+	random.Shuffle(len(influences), func(i, j int) {
+		influences[i], influences[j] = influences[j], influences[i]
+	})
+}
@@ -0,0 +1,80 @@
+package engine
+
+import "errors"
+
+var ErrCannotTargetOwnFaction = errors.New("cannot_target_own_faction")
+
+const (
+	AllegianceLoyalist  = "loyalist"
+	AllegianceReformist = "reformist"
+
+	// Converting yourself is cheaper than converting somebody else.
+	ConvertSelfCost  = 1
+	ConvertOtherCost = 2
+)
+
+// assignAllegiances splits the table into alternating factions, starting with the first player as a loyalist.
+func (game *Game) assignAllegiances() {
+	for offset := range game.Players {
+		player := game.Players[(game.StartingTurnIndex+offset)%len(game.Players)]
+
+		player.Allegiance = AllegianceLoyalist
+		if offset%2 == 1 {
+			player.Allegiance = AllegianceReformist
+		}
+	}
+}
+
+// sameFaction reports whether the two players are in the same faction while both factions are still alive.
+func (game *Game) sameFaction(playerID string, otherID string) bool {
+	if game.Settings.Ruleset != RulesetReformation || !game.bothFactionsAlive() {
+		return false
+	}
+
+	player, other := game.findPlayer(playerID), game.findPlayer(otherID)
+	return player != nil && other != nil && player.Allegiance == other.Allegiance
+}
+
+func (game *Game) bothFactionsAlive() bool {
+	loyalists, reformists := 0, 0
+	for _, player := range game.Players {
+		if !player.Alive {
+			continue
+		}
+		if player.Allegiance == AllegianceLoyalist {
+			loyalists++
+		} else {
+			reformists++
+		}
+	}
+	return loyalists > 0 && reformists > 0
+}
+
+// convert flips the allegiance of targetID, or of the actor when there is no target,
+// paying the conversion into the treasury reserve.
+func (game *Game) convert(actor *Player, targetID *string) error {
+	convertedPlayer, cost := actor, ConvertSelfCost
+
+	if targetID != nil && *targetID != actor.ID {
+		targetPlayer, err := game.findLivingTarget(targetID)
+		if err != nil {
+			return err
+		}
+		convertedPlayer, cost = targetPlayer, ConvertOtherCost
+	}
+
+	if actor.Coins < cost {
+		return ErrNotEnoughCoins
+	}
+
+	actor.Coins -= cost
+	game.TreasuryReserve += cost
+
+	if convertedPlayer.Allegiance == AllegianceLoyalist {
+		convertedPlayer.Allegiance = AllegianceReformist
+	} else {
+		convertedPlayer.Allegiance = AllegianceLoyalist
+	}
+
+	return nil
+}
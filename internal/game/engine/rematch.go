@@ -0,0 +1,115 @@
+package engine
+
+import "errors"
+
+var (
+	ErrGameNotFinished     = errors.New("game_not_finished")
+	ErrOnlyAdminCanRematch = errors.New("only_admin_can_rematch")
+)
+
+// rematch lets the admin deal a new game to the players of a finished one, with the same room and settings.
+func (game *Game) rematch(command Rematch) ([]Event, error) {
+	if !game.Finished {
+		return nil, ErrGameNotFinished
+	}
+	if game.AdminID != command.PlayerID {
+		return nil, ErrOnlyAdminCanRematch
+	}
+
+	previousWinnerID := ""
+	if game.WinnerID != nil {
+		previousWinnerID = *game.WinnerID
+	}
+
+	// A rematch after the end of a series starts a new one.
+	if game.SeriesFinished {
+		game.SeriesScores = nil
+		game.SeriesFinished = false
+	}
+
+	events, startEvents, err := game.dealNextGame(command.Seed, command.WinnerStarts)
+	if err != nil {
+		return nil, err
+	}
+
+	events = append(events, Event{
+		Type: "rematch_started",
+		Payload: map[string]any{
+			"previousWinnerID": previousWinnerID,
+			"firstPlayerID":    game.currentPlayer().ID,
+		},
+	})
+
+	return append(events, startEvents...), nil
+}
+
+/*
+dealNextGame clears the finished game and starts a new one with seed. It returns the events of
+the seats given up apart from those of the start, so callers can announce the new game in between.
+
+The players who left during the last game give up their seat. When winnerStarts is set
+and the last winner is still seated, they play first.
+*/
+func (game *Game) dealNextGame(seed int64, winnerStarts bool) ([]Event, []Event, error) {
+	previousWinnerID := ""
+	if game.WinnerID != nil {
+		previousWinnerID = *game.WinnerID
+	}
+
+	events := []Event{}
+	for _, player := range append([]*Player{}, game.Players...) {
+		if player.Left {
+			events = append(events, game.removePlayer(player.ID, false)...)
+		}
+	}
+
+	game.reset()
+
+	firstPlayerID := ""
+	if winnerStarts && game.findPlayer(previousWinnerID) != nil {
+		firstPlayerID = previousWinnerID
+	}
+
+	startEvents, err := game.startGame(StartGame{
+		PlayerID:      game.AdminID,
+		Seed:          seed,
+		FirstPlayerID: firstPlayerID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, startEvents, nil
+}
+
+// reset brings a finished game back to the lobby, keeping the room, its settings, its players and the series score.
+func (game *Game) reset() {
+	game.Started = false
+	game.Finished = false
+	game.WinnerID = nil
+
+	game.TurnIndex = 0
+	game.StartingTurnIndex = 0
+	game.TurnNumber = 0
+	game.Round = 0
+
+	game.Deck = []Influence{}
+	game.Treasury = 0
+	game.TreasuryReserve = 0
+	game.Seed = 0
+	game.RandomDraws = 0
+	game.DeckCommitment = ""
+
+	game.PendingAction = nil
+	game.PendingExchange = nil
+	game.PendingExamination = nil
+	game.PendingDrafts = nil
+	game.PendingInfluenceLosses = nil
+
+	for _, player := range game.Players {
+		player.Alive = true
+		player.Coins = 0
+		player.Influences = []Influence{}
+		player.Allegiance = ""
+	}
+}
@@ -0,0 +1,152 @@
+package engine
+
+import "errors"
+
+var ErrInvalidActionName = errors.New("invalid_action_name")
+
+// Role is a character card: the actions it can be claimed for, the actions it blocks and its card text.
+type Role interface {
+	Name() string
+	CardText() string
+	Actions() []ActionType
+	BlockedActions() []string
+}
+
+type role struct {
+	name           string
+	cardText       string
+	actions        []ActionType
+	blockedActions []string
+}
+
+func (role role) Name() string             { return role.name }
+func (role role) CardText() string         { return role.cardText }
+func (role role) Actions() []ActionType    { return role.actions }
+func (role role) BlockedActions() []string { return role.blockedActions }
+
+// NewRole describes a role that can be registered with RegisterRole.
+func NewRole(name string, cardText string, actions []ActionType, blockedActions []string) Role {
+	return role{name: name, cardText: cardText, actions: actions, blockedActions: blockedActions}
+}
+
+var (
+	roleRegistry = map[string]Role{}
+
+	// deckVariants lists the roles that make up the deck of each variant.
+	deckVariants = map[string][]string{}
+)
+
+// RegisterRole makes role available to deck variants, replacing a role with the same name.
+func RegisterRole(role Role) {
+	roleRegistry[role.Name()] = role
+}
+
+// LookupRole returns the registered role called name.
+func LookupRole(name string) (Role, bool) {
+	role, ok := roleRegistry[name]
+	return role, ok
+}
+
+// RegisterDeckVariant makes a deck made of roles selectable in the room settings.
+func RegisterDeckVariant(name string, roles []string) {
+	deckVariants[name] = roles
+}
+
+// DeckVariantRoles returns the roles of a registered deck variant.
+func DeckVariantRoles(variant string) ([]Role, bool) {
+	names, ok := deckVariants[variant]
+	if !ok {
+		return nil, false
+	}
+
+	roles := make([]Role, 0, len(names))
+	for _, name := range names {
+		if role, ok := roleRegistry[name]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles, true
+}
+
+func init() {
+	RegisterRole(NewRole("Duke", "Tax: take 3 coins. Blocks foreign aid.",
+		[]ActionType{{Name: "tax"}},
+		[]string{"foreign_aid"},
+	))
+	RegisterRole(NewRole("Assassin", "Assassinate: pay 3 coins, choose a player to lose an influence.",
+		[]ActionType{{Name: "assassinate", RequiresTarget: true, Cost: 3}},
+		nil,
+	))
+	RegisterRole(NewRole("Ambassador", "Exchange: draw 2 cards, return 2. Blocks stealing.",
+		[]ActionType{{Name: "exchange", Draw: 2}},
+		[]string{"steal"},
+	))
+	RegisterRole(NewRole("Captain", "Steal: take 2 coins from another player. Blocks stealing.",
+		[]ActionType{{Name: "steal", RequiresTarget: true}},
+		[]string{"steal"},
+	))
+	RegisterRole(NewRole("Contessa", "Blocks assassination.",
+		nil,
+		[]string{"assassinate"},
+	))
+	RegisterRole(NewRole("Inquisitor", "Exchange: draw 1 card, return 1. Examine: look at a card another player chooses to show you, you may force them to exchange it. Blocks stealing.",
+		[]ActionType{{Name: "exchange", Draw: 1}, {Name: "examine", RequiresTarget: true}},
+		[]string{"steal"},
+	))
+
+	RegisterDeckVariant(DeckVariantBase, []string{"Duke", "Assassin", "Ambassador", "Captain", "Contessa"})
+	RegisterDeckVariant(DeckVariantInquisitor, []string{"Duke", "Assassin", "Inquisitor", "Captain", "Contessa"})
+}
+
+// generalActions returns the actions anybody may take without claiming a role.
+func generalActions(settings Settings) []ActionType {
+	actions := []ActionType{
+		{Name: "income", IsImmediate: true},
+		{Name: "foreign_aid"},
+		{Name: "coup", IsImmediate: true, RequiresTarget: true, Cost: settings.CoupCost},
+	}
+
+	if settings.Ruleset == RulesetReformation {
+		actions = append(actions,
+			ActionType{Name: "convert", IsImmediate: true},
+			ActionType{Name: "embezzle", IsContestable: true, DeniedRole: "Duke"},
+		)
+	}
+
+	return actions
+}
+
+/*
+availableActions returns every action that can be declared in a room with settings,
+keyed by name, with the roles that claim and block each one filled in.
+*/
+func availableActions(settings Settings) map[string]ActionType {
+	roles, _ := DeckVariantRoles(settings.DeckVariant)
+	actions := map[string]ActionType{}
+
+	for _, action := range generalActions(settings) {
+		actions[action.Name] = action
+	}
+	for _, role := range roles {
+		for _, action := range role.Actions() {
+			action.ClaimedRole = role.Name()
+			action.IsContestable = true
+			actions[action.Name] = action
+		}
+	}
+
+	for name, action := range actions {
+		action.BlockableRoles = []Influence{}
+		for _, role := range roles {
+			for _, blockedAction := range role.BlockedActions() {
+				if blockedAction == name {
+					action.BlockableRoles = append(action.BlockableRoles, Influence{Role: role.Name()})
+				}
+			}
+		}
+		action.IsBlockable = len(action.BlockableRoles) > 0
+		actions[name] = action
+	}
+
+	return actions
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrInvalidSeriesWinTarget = errors.New("invalid_series_win_target")
+	ErrNoSeriesInProgress     = errors.New("no_series_in_progress")
+)
+
+// MaxSeriesWinTarget is the most wins a series can ask for.
+const MaxSeriesWinTarget = 10
+
+// SeriesStanding is how many games of the series a player has won.
+type SeriesStanding struct {
+	PlayerID string `json:"playerId"`
+	Nickname string `json:"nickname"`
+	Wins     int    `json:"wins"`
+}
+
+// seriesInProgress reports whether the room plays a series that nobody has won yet.
+func (game *Game) seriesInProgress() bool {
+	return game.Settings.SeriesWinTarget > 0 && !game.SeriesFinished
+}
+
+// SeriesContinues reports whether another game of the series has to be dealt once this one is over.
+func (game *Game) SeriesContinues() bool {
+	return game.Finished && game.seriesInProgress()
+}
+
+// scoreSeriesWin counts a game won by winnerID towards the series, finishing it once the target is reached.
+func (game *Game) scoreSeriesWin(winnerID string) {
+	if !game.seriesInProgress() {
+		return
+	}
+
+	if game.SeriesScores == nil {
+		game.SeriesScores = map[string]int{}
+	}
+	game.SeriesScores[winnerID]++
+
+	if game.SeriesScores[winnerID] >= game.Settings.SeriesWinTarget {
+		game.SeriesFinished = true
+	}
+}
+
+// SeriesStandings ranks the seated players by series wins, most wins first.
+func (game *Game) SeriesStandings() []SeriesStanding {
+	standings := make([]SeriesStanding, 0, len(game.Players))
+	for _, player := range game.Players {
+		standings = append(standings, SeriesStanding{
+			PlayerID: player.ID,
+			Nickname: player.Nickname,
+			Wins:     game.SeriesScores[player.ID],
+		})
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Wins > standings[j].Wins
+	})
+
+	return standings
+}
+
+// nextSeriesGame deals the next game of a series that is still being played.
+func (game *Game) nextSeriesGame(command NextSeriesGame) ([]Event, error) {
+	if !game.Finished {
+		return nil, ErrGameNotFinished
+	}
+	if !game.seriesInProgress() {
+		return nil, ErrNoSeriesInProgress
+	}
+
+	events, startEvents, err := game.dealNextGame(command.Seed, false)
+	if err != nil {
+		return nil, err
+	}
+
+	events = append(events, Event{
+		Type: "series_game_started",
+		Payload: map[string]any{
+			"standings": game.SeriesStandings(),
+		},
+	})
+
+	return append(events, startEvents...), nil
+}
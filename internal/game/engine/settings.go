@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidMaxPlayers            = errors.New("invalid_max_players")
+	ErrInvalidStartingCoins         = errors.New("invalid_starting_coins")
+	ErrInvalidCoupCost              = errors.New("invalid_coup_cost")
+	ErrInvalidForcedCoupCoins       = errors.New("invalid_forced_coup_coins")
+	ErrInvalidResponseTimeout       = errors.New("invalid_response_timeout")
+	ErrInvalidDeckVariant           = errors.New("invalid_deck_variant")
+	ErrInvalidRuleset               = errors.New("invalid_ruleset")
+	ErrInvalidDuelSettings          = errors.New("duel_needs_max_players_of_two")
+	ErrOnlyAdminCanChangeSettings   = errors.New("only_admin_can_change_settings")
+	ErrMaxPlayersBelowCurrentPlayer = errors.New("max_players_below_current_players")
+)
+
+const (
+	DefaultMaxPlayers = 6
+
+	// MaxResponseTimeout is the longest response window a room can ask for.
+	MaxResponseTimeout = 5 * time.Minute
+
+	DeckVariantBase       = "base"
+	DeckVariantInquisitor = "inquisitor" // the Inquisitor replaces the Ambassador
+
+	RulesetBase        = "base"
+	RulesetReformation = "reformation"
+)
+
+// Settings are the house rules of a room, chosen by the admin before the game starts.
+type Settings struct {
+	MaxPlayers      int    `json:"maxPlayers"`
+	StartingCoins   int    `json:"startingCoins"`
+	CoupCost        int    `json:"coupCost"`
+	ForcedCoupCoins int    `json:"forcedCoupCoins"`
+	DeckVariant     string `json:"deckVariant"`
+	Ruleset         string `json:"ruleset"`
+	Duel            bool   `json:"duel"` // two-player rules, see dealDuel
+
+	// SeriesWinTarget is how many games a player must win to take the series. Zero plays single games.
+	SeriesWinTarget int `json:"seriesWinTarget"`
+
+	// ResponseTimeoutSeconds bounds how long a response window stays open. Zero means no limit.
+	ResponseTimeoutSeconds int `json:"responseTimeoutSeconds"`
+}
+
+// SettingsUpdate changes only the settings that are set.
+type SettingsUpdate struct {
+	MaxPlayers             *int    `json:"maxPlayers,omitempty"`
+	StartingCoins          *int    `json:"startingCoins,omitempty"`
+	CoupCost               *int    `json:"coupCost,omitempty"`
+	ForcedCoupCoins        *int    `json:"forcedCoupCoins,omitempty"`
+	ResponseTimeoutSeconds *int    `json:"responseTimeoutSeconds,omitempty"`
+	DeckVariant            *string `json:"deckVariant,omitempty"`
+	Ruleset                *string `json:"ruleset,omitempty"`
+	Duel                   *bool   `json:"duel,omitempty"`
+	SeriesWinTarget        *int    `json:"seriesWinTarget,omitempty"`
+}
+
+// DefaultSettings returns the official rules.
+func DefaultSettings() Settings {
+	return Settings{
+		MaxPlayers:             DefaultMaxPlayers,
+		StartingCoins:          2,
+		CoupCost:               7,
+		ForcedCoupCoins:        ForcedCoupCoins,
+		ResponseTimeoutSeconds: 0,
+		DeckVariant:            DeckVariantBase,
+		Ruleset:                RulesetBase,
+	}
+}
+
+// Apply returns settings with the fields of update that are set.
+func (settings Settings) Apply(update SettingsUpdate) Settings {
+	if update.MaxPlayers != nil {
+		settings.MaxPlayers = *update.MaxPlayers
+	}
+	if update.StartingCoins != nil {
+		settings.StartingCoins = *update.StartingCoins
+	}
+	if update.CoupCost != nil {
+		settings.CoupCost = *update.CoupCost
+	}
+	if update.ForcedCoupCoins != nil {
+		settings.ForcedCoupCoins = *update.ForcedCoupCoins
+	}
+	if update.ResponseTimeoutSeconds != nil {
+		settings.ResponseTimeoutSeconds = *update.ResponseTimeoutSeconds
+	}
+	if update.DeckVariant != nil {
+		settings.DeckVariant = *update.DeckVariant
+	}
+	if update.Ruleset != nil {
+		settings.Ruleset = *update.Ruleset
+	}
+	if update.Duel != nil {
+		settings.Duel = *update.Duel
+	}
+	if update.SeriesWinTarget != nil {
+		settings.SeriesWinTarget = *update.SeriesWinTarget
+	}
+	return settings
+}
+
+// Validate checks that the settings make a playable game that the treasury can pay out.
+func (settings Settings) Validate() error {
+	if settings.MaxPlayers < MinPlayers || settings.MaxPlayers > MaxPlayers() {
+		return ErrInvalidMaxPlayers
+	}
+	if settings.CoupCost < 1 || settings.CoupCost > TreasurySupply {
+		return ErrInvalidCoupCost
+	}
+	if settings.ForcedCoupCoins < settings.CoupCost || settings.ForcedCoupCoins > TreasurySupply {
+		return ErrInvalidForcedCoupCoins
+	}
+	if settings.StartingCoins < 0 || settings.StartingCoins >= settings.ForcedCoupCoins {
+		return ErrInvalidStartingCoins
+	}
+	if settings.MaxPlayers*settings.StartingCoins > TreasurySupply {
+		return ErrTreasuryTooSmall
+	}
+	if settings.ResponseTimeoutSeconds < 0 || settings.ResponseTimeout() > MaxResponseTimeout {
+		return ErrInvalidResponseTimeout
+	}
+	if _, ok := deckVariants[settings.DeckVariant]; !ok {
+		return ErrInvalidDeckVariant
+	}
+	if settings.Ruleset != RulesetBase && settings.Ruleset != RulesetReformation {
+		return ErrInvalidRuleset
+	}
+	if settings.Duel && settings.MaxPlayers != 2 {
+		return ErrInvalidDuelSettings
+	}
+	if settings.SeriesWinTarget < 0 || settings.SeriesWinTarget > MaxSeriesWinTarget {
+		return ErrInvalidSeriesWinTarget
+	}
+	return nil
+}
+
+func (settings Settings) ResponseTimeout() time.Duration {
+	return time.Duration(settings.ResponseTimeoutSeconds) * time.Second
+}
+
+// updateSettings lets the admin change the settings of a game that has not started yet.
+func (game *Game) updateSettings(command UpdateSettings) ([]Event, error) {
+	if game.Started {
+		return nil, ErrAlreadyStarted
+	}
+
+	if game.AdminID != command.PlayerID {
+		return nil, ErrOnlyAdminCanChangeSettings
+	}
+
+	settings := game.Settings.Apply(command.Update)
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if settings.MaxPlayers < len(game.Players) {
+		return nil, ErrMaxPlayersBelowCurrentPlayer
+	}
+
+	game.Settings = settings
+
+	return []Event{
+		{
+			Type: "settings_updated",
+			Payload: map[string]any{
+				"settings": game.Settings,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAlreadyStarted        = errors.New("game_already_started")
+	ErrNotStarted            = errors.New("game_not_started")
+	ErrInvalidAction         = errors.New("invalid_action")
+	ErrPlayerAlreadyJoined   = errors.New("Player already joined with this nickname")
+	ErrGameAlreadyFinished   = errors.New("game_already_finished")
+	ErrOnlyAdminCanStartGame = errors.New("only_admin_can_start_game")
+	ErrOnlyAdminCanKick      = errors.New("only_admin_can_kick")
+	ErrNeedAtLeastTwoPlayers = errors.New("need_at_least_two_players")
+	ErrTooManyPlayers        = errors.New("too_many_players")
+	ErrRoomFull              = errors.New("room_full")
+	ErrNotEnoughInfluences   = errors.New("not_enough_influences")
+	ErrNotYourTurn           = errors.New("not_your_turn")
+	ErrActionAlreadyPending  = errors.New("action_already_pending")
+	ErrPendingActionNotFound = errors.New("pending_action_not_found")
+	ErrCannotRespondToSelf   = errors.New("cannot_respond_to_own_claim")
+	ErrAlreadyResponded      = errors.New("already_responded")
+	ErrActionNotContestable  = errors.New("action_not_contestable")
+	ErrPlayerNotFound        = errors.New("player_not_found")
+	ErrPlayerIsDead          = errors.New("player_is_dead")
+	ErrNotEnoughCoins        = errors.New("not_enough_coins")
+	ErrTargetPlayerNotFound  = errors.New("target_player_not_found")
+	ErrTargetPlayerIsDead    = errors.New("target_player_is_dead")
+	ErrCannotTargetSelf      = errors.New("cannot_target_self")
+	ErrActionNotBlockable    = errors.New("action_not_blockable")
+	ErrActionAlreadyBlocked  = errors.New("action_already_blocked")
+	ErrInvalidBlockRole      = errors.New("invalid_block_role")
+	ErrOnlyTargetCanBlock    = errors.New("only_target_can_block")
+)
+
+type Influence struct {
+	Role     string `json:"role"`
+	Revealed bool   `json:"revealed"`
+}
+
+type Player struct {
+	ID         string      `json:"id"`
+	Nickname   string      `json:"nickname"`
+	Coins      int         `json:"coins"`
+	Alive      bool        `json:"alive"`
+	Influences []Influence `json:"influences"`
+	Allegiance string      `json:"allegiance,omitempty"` // "loyalist", "reformist", only with the Reformation rules
+	Left       bool        `json:"left,omitempty"`       // left after the game started, the seat is freed on rematch
+}
+
+type Game struct {
+	ID        string
+	CreatedAt time.Time
+	AdminID   string
+	JoinCode  string
+	Players   []*Player
+	TurnIndex int
+	Started   bool
+	Finished  bool
+	WinnerID  *string `json:"winnerID,omitempty"`
+
+	Settings Settings `json:"settings"`
+
+	StartingTurnIndex int `json:"startingTurnIndex"`
+	TurnNumber        int `json:"turnNumber"`
+	Round             int `json:"round"`
+
+	Deck []Influence `json:"deck"`
+
+	// Seed and RandomDraws make every shuffle of the game reproducible, see random.
+	// The seed stays secret until the game is over, only DeckCommitment is public before.
+	Seed           int64  `json:"seed"`
+	RandomDraws    uint64 `json:"randomDraws"`
+	DeckCommitment string `json:"deckCommitment,omitempty"`
+
+	// Treasury holds the coins nobody has taken yet, see CoinSupply.
+	Treasury int `json:"treasury"`
+
+	// TreasuryReserve holds the coins paid for conversions, until someone embezzles them.
+	TreasuryReserve int `json:"treasuryReserve"`
+
+	// SeriesScores counts the games each player won in the current series.
+	SeriesScores   map[string]int `json:"seriesScores,omitempty"`
+	SeriesFinished bool           `json:"seriesFinished,omitempty"`
+
+	// ActionSequence numbers the pending actions of the room. It survives rematches,
+	// so an action ID never comes back.
+	ActionSequence int `json:"actionSequence"`
+
+	PendingAction          *PendingAction        `json:"pendingAction,omitempty"`
+	PendingExchange        *PendingExchange      `json:"pendingExchange,omitempty"`
+	PendingExamination     *PendingExamination   `json:"pendingExamination,omitempty"`
+	PendingDrafts          []DuelDraft           `json:"pendingDrafts,omitempty"`
+	PendingInfluenceLosses []InfluenceLossPrompt `json:"pendingInfluenceLosses,omitempty"`
+}
+
+/*
+⚠️ Warning:
+- this is a public representation of the influence, so it should not contain the role if it is not revealed
+*/
+type PublicInfluence struct {
+	Role     *string `json:"role,omitempty"`
+	Revealed bool    `json:"revealed"`
+}
+
+type PlayerPublicInfo struct {
+	ID         string            `json:"id"`
+	Nickname   string            `json:"nickname"`
+	Coins      int               `json:"coins"`
+	Alive      bool              `json:"alive"`
+	Influences []PublicInfluence `json:"influences"`
+	Allegiance string            `json:"allegiance,omitempty"`
+	Left       bool              `json:"left,omitempty"`
+}
+
+type PublicGameState struct {
+	GameID     string             `json:"gameID"`
+	JoinCode   string             `json:"joinCode"`
+	Started    bool               `json:"started"`
+	AdminID    string             `json:"adminID"`
+	Finished   bool               `json:"finished"`
+	WinnerID   *string            `json:"winnerID,omitempty"`
+	TurnIndex  int                `json:"turnIndex"`
+	TurnNumber int                `json:"turnNumber"`
+	Round      int                `json:"round"`
+	Players    []PlayerPublicInfo `json:"players"`
+	DeckLength int                `json:"deckLength"`
+	Settings   Settings           `json:"settings"`
+
+	Treasury        int `json:"treasury"`
+	TreasuryReserve int `json:"treasuryReserve"`
+
+	DeckCommitment string `json:"deckCommitment,omitempty"`
+	RevealedSeed   string `json:"revealedSeed,omitempty"` // only once the game is over
+
+	DraftingPlayerIDs []string `json:"draftingPlayerIDs,omitempty"`
+
+	SeriesScores   map[string]int `json:"seriesScores,omitempty"`
+	SeriesFinished bool           `json:"seriesFinished,omitempty"`
+
+	PendingAction          *PendingAction        `json:"pendingAction,omitempty"`
+	PendingInfluenceLosses []InfluenceLossPrompt `json:"pendingInfluenceLosses,omitempty"`
+}
+
+func (game *Game) findPlayer(playerID string) *Player {
+	for _, player := range game.Players {
+		if player.ID == playerID {
+			return player
+		}
+	}
+	return nil
+}
+
+// validateTarget makes sure targetID points to another player who is still in the game
+// and, under the Reformation ruleset, not of the same faction as actorID.
+func (game *Game) validateTarget(actorID string, targetID *string) (*Player, error) {
+	if targetID != nil && *targetID == actorID {
+		return nil, ErrCannotTargetSelf
+	}
+
+	targetPlayer, err := game.findLivingTarget(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if game.sameFaction(actorID, targetPlayer.ID) {
+		return nil, ErrCannotTargetOwnFaction
+	}
+
+	return targetPlayer, nil
+}
+
+// findLivingTarget returns the player behind targetID as long as they are still in the game.
+func (game *Game) findLivingTarget(targetID *string) (*Player, error) {
+	if targetID == nil {
+		return nil, errors.New("target_player_is_required")
+	}
+
+	targetPlayer := game.findPlayer(*targetID)
+	if targetPlayer == nil {
+		return nil, ErrTargetPlayerNotFound
+	}
+
+	if !targetPlayer.Alive || !targetPlayer.hasUnrevealedInfluence() {
+		return nil, ErrTargetPlayerIsDead
+	}
+
+	return targetPlayer, nil
+}
+
+func (player *Player) hasUnrevealedInfluence() bool {
+	for _, influence := range player.Influences {
+		if !influence.Revealed {
+			return true
+		}
+	}
+	return false
+}
+
+func (player *Player) hasUnrevealedRole(role string) bool {
+	for _, influence := range player.Influences {
+		if influence.Role == role && !influence.Revealed {
+			return true
+		}
+	}
+	return false
+}
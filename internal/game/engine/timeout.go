@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrResponseWindowOpen = errors.New("response_window_still_open")
+
+// startResponseWindow (re)starts the countdown of the pending action, if the room has a response timeout.
+func (game *Game) startResponseWindow(pendingAction *PendingAction, now time.Time) {
+	timeout := game.Settings.ResponseTimeout()
+	if timeout <= 0 {
+		pendingAction.ExpiresAt = nil
+		return
+	}
+
+	expiresAt := now.Add(timeout)
+	pendingAction.ExpiresAt = &expiresAt
+}
+
+// expireResponseWindow takes everyone who has not answered yet as passing once the window is over,
+// unless an influence loss is still pending.
+func (game *Game) expireResponseWindow(command ExpireResponseWindow) ([]Event, error) {
+	if err := game.ensureInProgress(); err != nil {
+		return nil, err
+	}
+	if err := game.ensureNoInfluenceLossPending(); err != nil {
+		return nil, err
+	}
+
+	pendingAction := game.PendingAction
+	if pendingAction == nil || pendingAction.ID != command.ActionID {
+		return nil, ErrPendingActionNotFound
+	}
+	if pendingAction.ExpiresAt == nil || command.Now.Before(*pendingAction.ExpiresAt) {
+		return nil, ErrResponseWindowOpen
+	}
+
+	for _, player := range game.Players {
+		if !pendingAction.hasPassed(player.ID) {
+			pendingAction.PassedIDs = append(pendingAction.PassedIDs, player.ID)
+		}
+	}
+
+	events := []Event{
+		{
+			Type: "response_window_expired",
+			Payload: map[string]any{
+				"actionID": pendingAction.ID,
+			},
+		},
+	}
+
+	return append(events, game.closeIfEveryonePassed()...), nil
+}
@@ -0,0 +1,47 @@
+package engine
+
+import "errors"
+
+var ErrTreasuryTooSmall = errors.New("treasury_too_small")
+
+// TreasurySupply is how many coins the game comes with.
+const TreasurySupply = 50
+
+/*
+CoinSupply counts every coin in play: the treasury, the Reformation reserve and what the
+players hold. Coins only ever move between them, so once a game has started it always
+equals TreasurySupply.
+*/
+func (game *Game) CoinSupply() int {
+	supply := game.Treasury + game.TreasuryReserve
+	for _, player := range game.Players {
+		supply += player.Coins
+	}
+	return supply
+}
+
+// takeFromTreasury pays player up to amount coins from the treasury and returns how many were paid.
+func (game *Game) takeFromTreasury(player *Player, amount int) int {
+	paid := min(amount, game.Treasury)
+
+	game.Treasury -= paid
+	player.Coins += paid
+
+	return paid
+}
+
+// payTreasury moves amount coins from player back to the treasury.
+func (game *Game) payTreasury(player *Player, amount int) {
+	player.Coins -= amount
+	game.Treasury += amount
+}
+
+// transferCoins moves up to amount coins from one player to another and returns how many moved.
+func transferCoins(from *Player, to *Player, amount int) int {
+	moved := min(amount, from.Coins)
+
+	from.Coins -= moved
+	to.Coins += moved
+
+	return moved
+}
@@ -0,0 +1,103 @@
+package engine
+
+import "errors"
+
+var ErrMustCoup = errors.New("must_coup")
+
+// ForcedCoupCoins is how many coins force a player to coup on their turn, unless the room says otherwise.
+const ForcedCoupCoins = 10
+
+// ensureInProgress fails unless the game has started and is not over yet.
+func (game *Game) ensureInProgress() error {
+	if game.Finished {
+		return ErrGameAlreadyFinished
+	}
+	if !game.Started {
+		return ErrNotStarted
+	}
+	return nil
+}
+
+// startTurns hands the first turn to the player at turnIndex.
+func (game *Game) startTurns(turnIndex int) {
+	game.TurnIndex = turnIndex
+	game.StartingTurnIndex = turnIndex
+	game.TurnNumber = 1
+	game.Round = 1
+}
+
+// currentPlayer returns the player whose turn it is.
+func (game *Game) currentPlayer() *Player {
+	return game.Players[game.TurnIndex]
+}
+
+// ensureCanDeclare checks that it is playerID's turn and that they are not forced to coup.
+func (game *Game) ensureCanDeclare(playerID string, actionName string) error {
+	turnPlayer := game.currentPlayer()
+	if turnPlayer.ID != playerID {
+		return ErrNotYourTurn
+	}
+
+	if turnPlayer.Coins >= game.Settings.ForcedCoupCoins && actionName != "coup" {
+		return ErrMustCoup
+	}
+
+	return nil
+}
+
+// advanceTurn passes the turn to the next player who is still alive. A new round
+// starts every time the turn goes past the seat of the starting player.
+// A finished game keeps the turn where it ended.
+func (game *Game) advanceTurn() {
+	if game.Finished {
+		return
+	}
+
+	for offset := 1; offset <= len(game.Players); offset++ {
+		nextIndex := (game.TurnIndex + offset) % len(game.Players)
+		if nextIndex == game.StartingTurnIndex {
+			game.Round++
+		}
+
+		if game.Players[nextIndex].Alive {
+			game.TurnIndex = nextIndex
+			game.TurnNumber++
+			return
+		}
+	}
+}
+
+// eliminateIfOut marks the player as dead once every influence is revealed and
+// finishes the game when a single player is left standing.
+func (game *Game) eliminateIfOut(player *Player) {
+	if player.hasUnrevealedInfluence() {
+		return
+	}
+	player.Alive = false
+
+	var lastAlive *Player
+	aliveCount := 0
+	for _, p := range game.Players {
+		if p.Alive {
+			lastAlive = p
+			aliveCount++
+		}
+	}
+
+	if aliveCount == 1 {
+		game.finish(lastAlive.ID)
+	}
+}
+
+// finish ends the game with winnerID as the winner, dropping whatever it was still waiting on.
+func (game *Game) finish(winnerID string) {
+	game.Finished = true
+	game.WinnerID = &winnerID
+	game.scoreSeriesWin(winnerID)
+
+	game.PendingAction = nil
+	game.PendingExchange = nil
+	game.PendingExamination = nil
+	game.PendingDrafts = nil
+	game.PendingInfluenceLosses = nil
+}
@@ -2,6 +2,7 @@ package game
 
 import (
 	"encoding/json"
+	"influence_game/internal/game/engine"
 	"influence_game/internal/realtime"
 	"time"
 
@@ -9,35 +10,81 @@ import (
 )
 
 type ServerEvent struct {
-	EventType string           `json:"eventType"`
-	GameID    string           `json:"gameID"`
-	Timestamp time.Time        `json:"timestamp"`
-	GameState *PublicGameState `json:"state,omitempty"`
-	Payload   map[string]any   `json:"payload,omitempty"`
+	EventType string                  `json:"eventType"`
+	GameID    string                  `json:"gameID"`
+	Timestamp time.Time               `json:"timestamp"`
+	GameState *engine.PublicGameState `json:"state,omitempty"`
+	Payload   map[string]any          `json:"payload,omitempty"`
 }
 
+// BroadcastEvent sends the event to everyone in the room, each with the state projected for them.
 func BroadcastEvent(
-	state *PublicGameState,
+	game *engine.Game,
 	eventType string,
 	payload map[string]any,
 ) {
-	if state == nil {
+	if game == nil {
 		return
 	}
 
+	timestamp := time.Now().UTC()
+
+	realtime.Manager.BroadcastPerPlayer(game.ID, func(playerID string) []byte {
+		return marshalEvent(game, playerID, eventType, timestamp, payload)
+	})
+}
+
+// SendPrivateEvent delivers an event to a single player, for information the rest of the room must not see.
+func SendPrivateEvent(
+	game *engine.Game,
+	playerID string,
+	eventType string,
+	payload map[string]any,
+) {
+	if game == nil {
+		return
+	}
+
+	data := marshalEvent(game, playerID, eventType, time.Now().UTC(), payload)
+	if data == nil {
+		return
+	}
+
+	realtime.Manager.SendToPlayer(game.ID, playerID, data)
+}
+
+func marshalEvent(
+	game *engine.Game,
+	playerID string,
+	eventType string,
+	timestamp time.Time,
+	payload map[string]any,
+) []byte {
 	ev := ServerEvent{
 		EventType: eventType,
-		GameID:    state.GameID,
-		Timestamp: time.Now().UTC(),
-		GameState: state,
+		GameID:    game.ID,
+		Timestamp: timestamp,
+		GameState: game.Project(game.ViewerFor(playerID)),
 		Payload:   payload,
 	}
 
 	data, err := json.Marshal(ev)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to marshal event.")
-		return
+		return nil
 	}
 
-	realtime.Manager.Broadcast(state.GameID, data)
+	return data
+}
+
+// publishEvents delivers the events produced by the engine, privately when they have a recipient.
+func publishEvents(game *engine.Game, events []engine.Event) {
+	for _, event := range events {
+		if event.RecipientID != "" {
+			SendPrivateEvent(game, event.RecipientID, event.Type, event.Payload)
+			continue
+		}
+
+		BroadcastEvent(game, event.Type, event.Payload)
+	}
 }
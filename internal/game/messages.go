@@ -1,6 +1,9 @@
 package game
 
-import "time"
+import (
+	"influence_game/internal/game/engine"
+	"time"
+)
 
 type WSMessageType string
 
@@ -14,9 +17,9 @@ const (
 )
 
 type WSMessage struct {
-	Type      WSMessageType    `json:"type"` // ex: "action_declared"
-	GameID    string           `json:"gameId"`
-	Timestamp time.Time        `json:"timestamp"`
-	GameState *PublicGameState `json:"gameState,omitempty"`
-	Payload   any              `json:"payload,omitempty"`
+	Type      WSMessageType           `json:"type"` // ex: "action_declared"
+	GameID    string                  `json:"gameId"`
+	Timestamp time.Time               `json:"timestamp"`
+	GameState *engine.PublicGameState `json:"gameState,omitempty"`
+	Payload   any                     `json:"payload,omitempty"`
 }
@@ -2,10 +2,15 @@ package game
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"influence_game/internal/game/engine"
+	"influence_game/internal/realtime"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,241 +18,97 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-type ActionType struct {
-	name            string
-	isImmediate     bool
-	isBlockable     bool
-	isContestable   bool
-	requiresTarget  bool
-	bloackableRoles []Influence
-	targetPlayerID  *string
-}
+var (
+	ErrGameNotFound   = errors.New("game_not_found")
+	ErrInvalidSession = errors.New("invalid_session")
+)
 
 type DeclareActionPayload struct {
 	ActionName     string  `json:"actionName"`
 	TargetPlayerID *string `json:"targetId,omitempty"`
 }
 
-// const (
-// 	ActionStart   ActionType = "start"
-// 	ActionEndTurn ActionType = "end_turn"
-
-// 	ActionIncome     ActionType = "income"
-// 	ActionForeignAid ActionType = "foreign_aid"
-// 	ActionCoup       ActionType = "coup"
-
-// 	ActionTax         ActionType = "tax"
-// 	ActionAssassinate ActionType = "assassinate"
-// 	ActionSteal       ActionType = "steal"
-// 	ActionExchange    ActionType = "exchange"
-
-// 	ActionBlockForeignAid  ActionType = "block_foreign_aid"
-// 	ActionBlockAssassinate ActionType = "block_assassinate"
-// 	ActionBlockSteal       ActionType = "block_steal"
-// )
-
-var (
-	ErrGameNotFound          = errors.New("game_not_found")
-	ErrAlreadyStarted        = errors.New("game_already_started")
-	ErrNotStarted            = errors.New("game_not_started")
-	ErrInvalidAction         = errors.New("invalid_action")
-	ErrPlayerAlreadyJoined   = errors.New("Player already joined with this nickname")
-	ErrGameAlreadyFinished   = errors.New("game_already_finished")
-	ErrOnlyAdminCanStartGame = errors.New("only_admin_can_start_game")
-	ErrNeedAtLeastTwoPlayers = errors.New("need_at_least_two_players")
-	ErrTooManyPlayers        = errors.New("too_many_players")
-	ErrInvalidSession        = errors.New("invalid_session")
-	ErrNotEnoughInfluences   = errors.New("not_enough_influences")
-)
-
-type Influence struct {
-	Role     string `json:"role"`
-	Revealed bool   `json:"revealed"`
-}
-
-type Player struct {
-	ID         string      `json:"id"`
-	Nickname   string      `json:"nickname"`
-	Coins      int         `json:"coins"`
-	Alive      bool        `json:"alive"`
-	Influences []Influence `json:"influences"`
+type PlayerSession struct {
+	PlayerID string `json:"playerId"`
+	GameID   string `json:"gameId"`
 }
 
-type Game struct {
-	ID        string
-	CreatedAt time.Time
-	AdminID   string
-	JoinCode  string
-	Players   []*Player
-	TurnIndex int
-	Started   bool
-	Finished  bool
+type Store struct {
+	redis *redis.Client
 
-	Deck []Influence `json:"deck"`
+	randMu sync.Mutex
+	rand   *rand.Rand
 }
 
-type PlayerSession struct {
-	PlayerID string `json:"playerId"`
-	GameID   string `json:"gameId"`
+func NewStore(redisClient *redis.Client) *Store {
+	return &Store{
+		redis: redisClient,
+		rand:  rand.New(cryptoSource{}),
+	}
 }
 
 /*
+SetRandSource replaces where the store draws join codes and game seeds from.
+
+Tests and replays use it to get the exact same rooms, deals and starting players.
+
 ⚠️ Warning:
-- this is a public representation of the influence, so it should not contain the role if it is not revealed
+- never set it in production: revealed seeds would let players predict the next decks
 */
-type PublicInfluence struct {
-	Role     *string `json:"role,omitempty"`
-	Revealed bool    `json:"revealed"`
-}
-
-type PlayerPublicInfo struct {
-	ID         string            `json:"id"`
-	Nickname   string            `json:"nickname"`
-	Coins      int               `json:"coins"`
-	Alive      bool              `json:"alive"`
-	Influences []PublicInfluence `json:"influences"`
-}
-
-type PublicGameState struct {
-	GameID     string             `json:"gameID"`
-	JoinCode   string             `json:"joinCode"`
-	Started    bool               `json:"started"`
-	AdminID    string             `json:"adminID"`
-	Finished   bool               `json:"finished"`
-	TurnIndex  int                `json:"turnIndex"`
-	Players    []PlayerPublicInfo `json:"players"`
-	DeckLength int                `json:"deckLength"`
-}
-
-type PendingAction struct {
-	ID        string     `json:"id"`
-	ActorID   string     `json:"actorId"`
-	Action    ActionType `json:"action"`
-	TargetID  *string    `json:"targetId,omitempty"`
-	CreatedAt time.Time  `json:"createdAt"`
-	Status    string     `json:"status"` // "declared", "resolved", "canceled"...
-}
-
-func (game *Game) GetPublicGameState() *PublicGameState {
-	playersPublicInfo := make([]PlayerPublicInfo, 0, len(game.Players))
-
-	for _, player := range game.Players {
-		if player.ID == game.AdminID {
-			influences := make([]PublicInfluence, 0, len(player.Influences))
-
-			for _, influence := range player.Influences {
-				influences = append(influences, PublicInfluence{
-					Role:     &influence.Role,
-					Revealed: influence.Revealed,
-				})
-			}
+func (store *Store) SetRandSource(source rand.Source) {
+	store.randMu.Lock()
+	defer store.randMu.Unlock()
 
-			playersPublicInfo = append(playersPublicInfo, PlayerPublicInfo{
-				ID:         player.ID,
-				Nickname:   player.Nickname,
-				Coins:      player.Coins,
-				Alive:      player.Alive,
-				Influences: influences,
-			})
-		} else {
-			playersPublicInfo = append(playersPublicInfo, getPublicPlayerInfo(player))
-		}
-	}
+	store.rand = rand.New(source)
+}
 
-	return &PublicGameState{
-		GameID:     game.ID,
-		JoinCode:   game.JoinCode,
-		Started:    game.Started,
-		Finished:   game.Finished,
-		TurnIndex:  game.TurnIndex,
-		Players:    playersPublicInfo,
-		AdminID:    game.AdminID,
-		DeckLength: len(game.Deck),
-	}
+// randomInt63n draws from the random source of the store, which is not safe for concurrent use on its own.
+func (store *Store) randomInt63n(n int64) int64 {
+	store.randMu.Lock()
+	defer store.randMu.Unlock()
+
+	return store.rand.Int63n(n)
 }
 
-func getPublicPlayerInfo(player *Player) PlayerPublicInfo {
-	influences := make([]PublicInfluence, 0, len(player.Influences))
-	for _, influence := range player.Influences {
-		if influence.Revealed {
-			influences = append(influences, PublicInfluence{
-				Role:     &influence.Role,
-				Revealed: influence.Revealed,
-			})
-		} else {
-			influences = append(influences, PublicInfluence{
-				Role:     nil,
-				Revealed: influence.Revealed,
-			})
-		}
-	}
-	return PlayerPublicInfo{
-		ID:         player.ID,
-		Nickname:   player.Nickname,
-		Coins:      player.Coins,
-		Alive:      player.Alive,
-		Influences: influences,
-	}
-}
-
-// func (g *Game) HandleAction(action ActionType, body json.RawMessage) error {
-// 	switch action {
-// 	case ActionStart:
-// 		if g.Started {
-// 			return ErrAlreadyStarted
-// 		}
-// 		if len(g.Players) < 2 {
-// 			return errors.New("need_at_least_two_players")
-// 		}
-// 		g.Started = true
-// 		for _, p := range g.Players {
-// 			p.Coins = 2
-// 			p.Alive = true
-// 		}
-// 		return nil
-// 	case ActionIncome:
-// 		if !g.Started {
-// 			return ErrNotStarted
-// 		}
-// 		cur := g.Players[g.TurnIndex%len(g.Players)]
-// 		if !cur.Alive {
-// 			g.TurnIndex = (g.TurnIndex + 1) % len(g.Players)
-// 			return nil
-// 		}
-// 		cur.Coins++
-// 		g.TurnIndex = (g.TurnIndex + 1) % len(g.Players)
-// 		return nil
-
-// 		// TODO: Implement other actions
-// 	default:
-// 		return ErrInvalidAction
-// 	}
-// }
+// newSeed draws the seed of a new game.
+func (store *Store) newSeed() int64 {
+	store.randMu.Lock()
+	defer store.randMu.Unlock()
 
-type Store struct {
-	redis *redis.Client
+	return store.rand.Int63()
 }
 
-func NewStore(redisClient *redis.Client) *Store {
-	return &Store{
-		redis: redisClient,
-	}
+// cryptoSource is the default random source of the store, backed by crypto/rand so that
+// neither join codes nor revealed seeds let anyone predict the next seeds.
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 {
+	var b [8]byte
+	// crypto/rand.Read never fails, see its documentation.
+	_, _ = cryptorand.Read(b[:])
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1)
 }
 
+func (cryptoSource) Seed(int64) {}
+
 func (store *Store) GetRedis() *redis.Client {
 	return store.redis
 }
 
 type OnboardingResult struct {
-	Game   *PublicGameState `json:"game"`
-	Player *Player          `json:"player"`
-	Token  string           `json:"token"`
+	Game   *engine.PublicGameState `json:"game"`
+	Player *engine.Player          `json:"player"`
+	Token  string                  `json:"token"`
 }
 
-func (store *Store) CreateGameRoom(adminNickname string) (*OnboardingResult, error) {
-	adminPlayer := buildNewPlayer(adminNickname)
+func (store *Store) CreateGameRoom(adminNickname string, settings engine.Settings) (*OnboardingResult, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	adminPlayer := engine.NewPlayer(uuid.NewString(), adminNickname)
 
-	newGame, err := store.buildNewGame(adminPlayer)
+	newGame, err := store.buildNewGame(adminPlayer, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +124,7 @@ func (store *Store) CreateGameRoom(adminNickname string) (*OnboardingResult, err
 		return nil, err
 	}
 
-	publicState := newGame.GetPublicGameState()
+	publicState := newGame.Project(newGame.ViewerFor(adminPlayer.ID))
 
 	return &OnboardingResult{
 		Game:   publicState,
@@ -272,17 +133,7 @@ func (store *Store) CreateGameRoom(adminNickname string) (*OnboardingResult, err
 	}, nil
 }
 
-func buildNewPlayer(nickname string) *Player {
-	return &Player{
-		ID:         uuid.NewString(),
-		Nickname:   nickname,
-		Coins:      2,
-		Alive:      true,
-		Influences: []Influence{},
-	}
-}
-
-func (store *Store) buildNewGame(adminPlayer *Player) (*Game, error) {
+func (store *Store) buildNewGame(adminPlayer *engine.Player, settings engine.Settings) (*engine.Game, error) {
 	gameID := uuid.NewString()
 
 	joinCode, err := store.reserveJoinCode(gameID)
@@ -290,27 +141,15 @@ func (store *Store) buildNewGame(adminPlayer *Player) (*Game, error) {
 		return nil, fmt.Errorf("failed to generate unique join code: %w", err)
 	}
 
-	game := &Game{
-		ID:        gameID,
-		CreatedAt: time.Now(),
-		Players:   []*Player{adminPlayer},
-		JoinCode:  joinCode,
-		AdminID:   adminPlayer.ID,
-		TurnIndex: 0,
-		Started:   false,
-		Finished:  false,
-		Deck:      []Influence{},
-	}
-
-	return game, nil
+	return engine.NewGame(gameID, joinCode, adminPlayer, settings, time.Now()), nil
 }
 
 const letters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 
-func randomJoinCode() string {
+func (store *Store) randomJoinCode() string {
 	b := make([]byte, 6)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[store.randomInt63n(int64(len(letters)))]
 	}
 	return string(b)
 }
@@ -319,7 +158,7 @@ func (store *Store) reserveJoinCode(gameID string) (string, error) {
 	ctx := context.Background()
 
 	for {
-		code := randomJoinCode()
+		code := store.randomJoinCode()
 		key := "joincode:" + code
 
 		ok, err := store.redis.SetNX(ctx, key, gameID, JoinCodeTTL).Result()
@@ -333,7 +172,7 @@ func (store *Store) reserveJoinCode(gameID string) (string, error) {
 	}
 }
 
-func (store *Store) saveGameToRedis(newGame *Game) error {
+func (store *Store) saveGameToRedis(newGame *engine.Game) error {
 	serializedGame, err := json.Marshal(newGame)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to serialize game.")
@@ -373,7 +212,11 @@ func (store *Store) CreatePlayerSession(gameID string, playerID string) (string,
 
 	redisKey := "session:" + sessionToken
 
-	err = store.redis.Set(ctx, redisKey, data, SessionDuration).Err()
+	_, err = store.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisKey, data, SessionDuration)
+		pipe.Set(ctx, "playersession:"+playerID, sessionToken, SessionDuration)
+		return nil
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to save session to Redis.")
 		return "", err
@@ -382,122 +225,104 @@ func (store *Store) CreatePlayerSession(gameID string, playerID string) (string,
 	return sessionToken, nil
 }
 
-func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
+// getSession loads the session behind sessionToken and makes sure it belongs to gameID.
+func (store *Store) getSession(gameID string, sessionToken string) (*PlayerSession, error) {
 	ctx := context.Background()
 
-	joinKey := "joincode:" + joinCode
-	gameID, err := store.redis.Get(ctx, joinKey).Result()
+	sessionKey := "session:" + sessionToken
+	sessionJSON, err := store.redis.Get(ctx, sessionKey).Bytes()
 	if err == redis.Nil {
-		return nil, ErrGameNotFound
+		log.Error().Msg("Session not found.")
+		return nil, ErrInvalidSession
 	}
 	if err != nil {
+		log.Error().Err(err).Msg("Failed to get session from Redis.")
 		return nil, err
 	}
 
-	gameKey := "game:" + gameID
-
-	var joinedPlayer *Player
-	var finalGame Game
-
-	for {
-		err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
-			gameJSON, err := tx.Get(ctx, gameKey).Bytes()
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to get game from Redis.")
-				return err
-			}
-
-			if err := json.Unmarshal(gameJSON, &finalGame); err != nil {
-				log.Error().Err(err).Msg("Failed to unmarshal game from Redis.")
-				return err
-			}
-
-			if finalGame.Started {
-				log.Error().Msg("Game already started.")
-				return ErrAlreadyStarted
-			}
-
-			for _, p := range finalGame.Players {
-				if p.Nickname == nickname {
-					log.Error().Err(err).Msg("Player already joined with this nickname.")
-					return ErrPlayerAlreadyJoined
-				}
-			}
-
-			joinedPlayer = buildNewPlayer(nickname)
-			finalGame.Players = append(finalGame.Players, joinedPlayer)
-
-			updatedJSON, _ := json.Marshal(finalGame)
-
-			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-				pipe.Set(ctx, gameKey, updatedJSON, 0)
-				return nil
-			})
+	var session PlayerSession
+	if err := json.Unmarshal(sessionJSON, &session); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal session from Redis.")
+		return nil, err
+	}
 
-			return err
-		}, gameKey)
+	if session.GameID != gameID {
+		log.Error().Msg("Invalid session game ID.")
+		return nil, ErrInvalidSession
+	}
 
-		if err == redis.TxFailedErr {
-			continue
-		}
+	return &session, nil
+}
 
-		if err != nil {
-			return nil, err
-		}
+// loadGame reads the current state of gameID from Redis.
+func (store *Store) loadGame(gameID string) (*engine.Game, error) {
+	ctx := context.Background()
 
-		break
+	gameJSON, err := store.redis.Get(ctx, "game:"+gameID).Bytes()
+	if err == redis.Nil {
+		log.Error().Msg("Game not found.")
+		return nil, ErrGameNotFound
 	}
-
-	sessionToken, err := store.CreatePlayerSession(gameID, joinedPlayer.ID)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create player session.")
+		log.Error().Err(err).Msg("Failed to get game from Redis.")
 		return nil, err
 	}
 
-	BroadcastEvent(
-		finalGame.GetPublicGameState(),
-		"player_joined",
-		map[string]any{
-			"newPlayer": joinedPlayer,
-		},
-	)
+	var game engine.Game
+	if err := json.Unmarshal(gameJSON, &game); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal game from Redis.")
+		return nil, err
+	}
 
-	return &OnboardingResult{
-		Game:   finalGame.GetPublicGameState(),
-		Player: joinedPlayer,
-		Token:  sessionToken,
-	}, nil
+	return &game, nil
 }
 
-func (store *Store) StartGame(gameID string, sessionToken string) (*PublicGameState, error) {
+// invalidatePlayerSession deletes the session of playerID so its token stops working.
+func (store *Store) invalidatePlayerSession(playerID string) error {
 	ctx := context.Background()
+	indexKey := "playersession:" + playerID
 
-	sessionKey := "session:" + sessionToken
-	sessionJSON, err := store.redis.Get(ctx, sessionKey).Bytes()
+	sessionToken, err := store.redis.Get(ctx, indexKey).Result()
 	if err == redis.Nil {
-		log.Error().Msg("Session not found.")
-		return nil, ErrInvalidSession
+		return nil
 	}
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get session from Redis.")
-		return nil, err
+		log.Error().Err(err).Msg("Failed to get player session from Redis.")
+		return err
 	}
 
-	var session PlayerSession
-	if err := json.Unmarshal(sessionJSON, &session); err != nil {
-		log.Error().Err(err).Msg("Failed to unmarshal session from Redis.")
-		return nil, err
+	if err := store.redis.Del(ctx, "session:"+sessionToken, indexKey).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to delete session from Redis.")
+		return err
 	}
 
-	if session.GameID != gameID {
-		log.Error().Msg("Invalid session game ID.")
-		return nil, ErrInvalidSession
+	return nil
+}
+
+// deleteRoom removes an abandoned game together with its join code.
+func (store *Store) deleteRoom(game *engine.Game) error {
+	ctx := context.Background()
+
+	if err := store.redis.Del(ctx, "game:"+game.ID, "joincode:"+game.JoinCode, "seriesnext:"+game.ID).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to delete game from Redis.")
+		return err
 	}
 
-	playerID := session.PlayerID
+	return nil
+}
 
+/*
+applyCommand loads the game, runs command through the rules engine and saves the new
+state inside a WATCH transaction. The events produced by the command are published
+once the new state is stored.
+*/
+func (store *Store) applyCommand(gameID string, command engine.Command) (*engine.Game, error) {
+	ctx := context.Background()
 	gameKey := "game:" + gameID
 
+	var previousGame, newGame *engine.Game
+	var events []engine.Event
+
 	for {
 		err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
 			gameJSON, err := tx.Get(ctx, gameKey).Bytes()
@@ -510,65 +335,26 @@ func (store *Store) StartGame(gameID string, sessionToken string) (*PublicGameSt
 				return err
 			}
 
-			var game Game
+			var game engine.Game
 			if err := json.Unmarshal(gameJSON, &game); err != nil {
 				log.Error().Err(err).Msg("Failed to unmarshal game from Redis.")
 				return err
 			}
 
-			if game.Started {
-				log.Error().Msg("Game already started.")
-				return ErrAlreadyStarted
-			}
-			if game.Finished {
-				log.Error().Msg("Game already finished.")
-				return ErrGameAlreadyFinished
-			}
-
-			if game.AdminID != playerID {
-				log.Error().Msg("Only admin can start game.")
-				return ErrOnlyAdminCanStartGame
-			}
-
-			if len(game.Players) <= 2 {
-				log.Error().Msg("Need at least two players.")
-				return ErrNeedAtLeastTwoPlayers
-			}
-			if len(game.Players) >= 7 {
-				log.Error().Msg("Too many players.")
-				return ErrTooManyPlayers
-			}
-
-			game.Started = true
-			game.TurnIndex = rand.Intn(len(game.Players)) // Is it really random?
-			deck := NewBaseDeck()
-
-			rand.Shuffle(len(deck), func(i, j int) {
-				deck[i], deck[j] = deck[j], deck[i]
-			})
-
-			neededCards := len(game.Players) * 2
-			if neededCards > len(deck) {
-				log.Error().Msg("Not enough influences.")
-				return ErrNotEnoughInfluences
+			previousGame = &game
+			newGame, events, err = engine.Apply(&game, command)
+			if err != nil {
+				return err
 			}
 
-			for _, p := range game.Players {
-				p.Coins = 2
-				p.Alive = true
-				p.Influences = make([]Influence, 0, 2)
-
-				p.Influences = append(p.Influences, deck[0], deck[1])
-
-				deck = deck[2:]
+			updatedJSON, err := json.Marshal(newGame)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to serialize game.")
+				return err
 			}
 
-			game.Deck = deck
-
-			updatedGameJSON, _ := json.Marshal(game)
-
 			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-				pipe.Set(ctx, gameKey, updatedGameJSON, 0)
+				pipe.Set(ctx, gameKey, updatedJSON, 0)
 				return nil
 			})
 
@@ -576,245 +362,353 @@ func (store *Store) StartGame(gameID string, sessionToken string) (*PublicGameSt
 		}, gameKey)
 
 		if err == redis.TxFailedErr {
-			log.Error().Msg("Transaction failed.")
 			continue
 		}
-
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to start game.")
 			return nil, err
 		}
 
 		break
 	}
 
-	updatedGameJSON, err := store.redis.Get(ctx, "game:"+gameID).Bytes()
-	if err != nil {
-		return nil, err
+	publishEvents(newGame, events)
+	store.scheduleResponseTimeout(previousGame, newGame)
+	store.scheduleNextSeriesGame(previousGame, newGame)
+
+	return newGame, nil
+}
+
+// scheduleNextSeriesGame deals the next game of the series once the one that just ended has been on screen for a while.
+// The time it is due is saved too, so dealOverdueSeriesGame can catch up after a restart.
+func (store *Store) scheduleNextSeriesGame(previousGame, newGame *engine.Game) {
+	if previousGame.Finished || !newGame.SeriesContinues() {
+		return
 	}
 
-	var game Game
-	if err := json.Unmarshal(updatedGameJSON, &game); err != nil {
+	ctx := context.Background()
+	dueAt := time.Now().Add(SeriesNextGameDelay)
+	if err := store.redis.Set(ctx, "seriesnext:"+newGame.ID, dueAt.UnixMilli(), SessionDuration).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to save when the next series game is due.")
+	}
+
+	gameID := newGame.ID
+	afterFunc(SeriesNextGameDelay, func() {
+		_, _ = store.dealNextSeriesGame(gameID)
+	})
+}
+
+// dealNextSeriesGame deals the next game of the series played in gameID.
+func (store *Store) dealNextSeriesGame(gameID string) (*engine.Game, error) {
+	newGame, err := store.applyCommand(gameID, engine.NextSeriesGame{Seed: store.newSeed()})
+	// The admin may have dealt a rematch in the meantime, or another server got there first.
+	if err != nil {
+		log.Debug().Err(err).Msg("Next series game was not dealt.")
 		return nil, err
 	}
 
-	BroadcastEvent(
-		game.GetPublicGameState(),
-		"game_started",
-		nil,
-	)
+	if err := store.redis.Del(context.Background(), "seriesnext:"+gameID).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to clear when the next series game was due.")
+	}
 
-	return game.GetPublicGameState(), nil
+	return newGame, nil
 }
 
-func NewBaseDeck() []Influence {
-	return []Influence{
-		{Role: "Duke"},
-		{Role: "Duke"},
-		{Role: "Duke"},
+// dealOverdueSeriesGame deals the next game of the series of game if it is due and its timer was lost.
+func (store *Store) dealOverdueSeriesGame(game *engine.Game) *engine.Game {
+	if !game.SeriesContinues() {
+		return game
+	}
 
-		{Role: "Assassin"},
-		{Role: "Assassin"},
-		{Role: "Assassin"},
+	dueAt, err := store.redis.Get(context.Background(), "seriesnext:"+game.ID).Int64()
+	if err != nil && err != redis.Nil {
+		log.Error().Err(err).Msg("Failed to get when the next series game is due.")
+		return game
+	}
+	if err == nil && time.Now().UnixMilli() < dueAt {
+		return game
+	}
 
-		{Role: "Ambassador"},
-		{Role: "Ambassador"},
-		{Role: "Ambassador"},
+	newGame, err := store.dealNextSeriesGame(game.ID)
+	if err != nil {
+		return game
+	}
+	return newGame
+}
 
-		{Role: "Captain"},
-		{Role: "Captain"},
-		{Role: "Captain"},
+// scheduleResponseTimeout expires the response window of newGame once its time is up, whenever the
+// command just applied opened or restarted it, or answered the last influence loss holding it.
+func (store *Store) scheduleResponseTimeout(previousGame, newGame *engine.Game) {
+	pendingAction := newGame.PendingAction
+	if pendingAction == nil || pendingAction.ExpiresAt == nil || len(newGame.PendingInfluenceLosses) > 0 {
+		return
+	}
 
-		{Role: "Contessa"},
-		{Role: "Contessa"},
-		{Role: "Contessa"},
+	previousAction := previousGame.PendingAction
+	promptsCleared := len(previousGame.PendingInfluenceLosses) > 0
+	if !promptsCleared && previousAction != nil && previousAction.ExpiresAt != nil && previousAction.ExpiresAt.Equal(*pendingAction.ExpiresAt) {
+		return
 	}
+
+	gameID, actionID := newGame.ID, pendingAction.ID
+	afterFunc(time.Until(*pendingAction.ExpiresAt), func() {
+		_, err := store.applyCommand(gameID, engine.ExpireResponseWindow{
+			ActionID: actionID,
+			Now:      time.Now().UTC(),
+		})
+		// The players usually answer before the window closes, so most timers find nothing to expire.
+		if err != nil {
+			log.Debug().Err(err).Msg("Response window was not expired.")
+		}
+	})
 }
 
-func (store *Store) DeclareAction(
+// afterFunc runs fn in its own goroutine once delay is over, logging a panic instead of taking the server down.
+func afterFunc(delay time.Duration, fn func()) {
+	time.AfterFunc(delay, func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Error().Interface("panic", recovered).Msg("Scheduled command panicked.")
+			}
+		}()
+
+		fn()
+	})
+}
+
+// applyPlayerCommand authenticates sessionToken against gameID and applies the command built for that player.
+func (store *Store) applyPlayerCommand(
 	gameID string,
-	action DeclareActionPayload,
 	sessionToken string,
-) (*PublicGameState, error) {
+	buildCommand func(playerID string) engine.Command,
+) (*engine.PublicGameState, error) {
+	session, err := store.getSession(gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	resultGame, err := store.applyCommand(gameID, buildCommand(session.PlayerID))
+	if err != nil {
+		return nil, err
+	}
+
+	return resultGame.Project(resultGame.ViewerFor(session.PlayerID)), nil
+}
+
+func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
 	ctx := context.Background()
 
-	sessionKey := "session:" + sessionToken
-	sessionJSON, err := store.redis.Get(ctx, sessionKey).Bytes()
+	joinKey := "joincode:" + joinCode
+	gameID, err := store.redis.Get(ctx, joinKey).Result()
 	if err == redis.Nil {
-		return nil, ErrInvalidSession
+		return nil, ErrGameNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	var session PlayerSession
-	if err := json.Unmarshal(sessionJSON, &session); err != nil {
+	joinedPlayerID := uuid.NewString()
+
+	finalGame, err := store.applyCommand(gameID, engine.JoinGame{
+		PlayerID: joinedPlayerID,
+		Nickname: nickname,
+	})
+	if err != nil {
 		return nil, err
 	}
-	if session.GameID != gameID {
-		return nil, ErrInvalidSession
+
+	sessionToken, err := store.CreatePlayerSession(gameID, joinedPlayerID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create player session.")
+		return nil, err
 	}
-	actingPlayerID := session.PlayerID
 
-	gameKey := "game:" + gameID
+	var joinedPlayer *engine.Player
+	for _, player := range finalGame.Players {
+		if player.ID == joinedPlayerID {
+			joinedPlayer = player
+		}
+	}
+
+	return &OnboardingResult{
+		Game:   finalGame.Project(finalGame.ViewerFor(joinedPlayerID)),
+		Player: joinedPlayer,
+		Token:  sessionToken,
+	}, nil
+}
+
+func (store *Store) StartGame(gameID string, sessionToken string) (*engine.PublicGameState, error) {
+	seed := store.newSeed()
+
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.StartGame{PlayerID: playerID, Seed: seed}
+	})
+}
 
-	var resultGame Game
-	actionType, err := buildActionType(action)
+func (store *Store) DeclareAction(
+	gameID string,
+	action DeclareActionPayload,
+	sessionToken string,
+) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.DeclareAction{
+			PlayerID:       playerID,
+			ActionName:     action.ActionName,
+			TargetPlayerID: action.TargetPlayerID,
+			Now:            time.Now().UTC(),
+		}
+	})
+}
+
+// PassAction records that the player behind sessionToken lets the pending action (or its block) go through.
+func (store *Store) PassAction(gameID, actionID, sessionToken string) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.PassAction{PlayerID: playerID, ActionID: actionID}
+	})
+}
+
+// BlockAction lets the player behind sessionToken block the pending action by claiming role.
+func (store *Store) BlockAction(gameID, actionID, sessionToken, role string) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.BlockAction{PlayerID: playerID, ActionID: actionID, Role: role, Now: time.Now().UTC()}
+	})
+}
+
+// ChallengeAction lets the player behind sessionToken challenge the claim that is currently open.
+func (store *Store) ChallengeAction(gameID, actionID, sessionToken string) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.ChallengeAction{PlayerID: playerID, ActionID: actionID, Now: time.Now().UTC()}
+	})
+}
+
+// RevealInfluence answers the lose-influence prompt of the player behind sessionToken.
+func (store *Store) RevealInfluence(gameID, sessionToken string, influenceIndex int) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.RevealInfluence{PlayerID: playerID, InfluenceIndex: influenceIndex, Now: time.Now().UTC()}
+	})
+}
+
+// ExchangeCards completes the exchange of the player behind sessionToken, keeping the given roles.
+func (store *Store) ExchangeCards(gameID, sessionToken string, keep []string) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.ExchangeCards{PlayerID: playerID, Keep: keep}
+	})
+}
+
+// GetGameState returns the state of gameID as seen by the player behind sessionToken.
+func (store *Store) GetGameState(gameID, sessionToken string) (*engine.PublicGameState, error) {
+	session, err := store.getSession(gameID, sessionToken)
 	if err != nil {
 		return nil, err
 	}
 
-	for {
-		err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
-			gameJSON, err := tx.Get(ctx, gameKey).Bytes()
-			if err == redis.Nil {
-				return ErrGameNotFound
-			}
-			if err != nil {
-				return err
-			}
+	game, err := store.loadGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	game = store.dealOverdueSeriesGame(game)
 
-			var game Game
-			if err := json.Unmarshal(gameJSON, &game); err != nil {
-				return err
-			}
+	return game.Project(game.ViewerFor(session.PlayerID)), nil
+}
 
-			if !game.Started || game.Finished {
-				return ErrNotStarted
-			}
+// GetPrivateView returns the hand and pending prompts of the player behind sessionToken.
+func (store *Store) GetPrivateView(gameID, sessionToken string) (*engine.PrivateView, error) {
+	session, err := store.getSession(gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
 
-			turnPlayer := game.Players[game.TurnIndex]
-			if turnPlayer.ID != actingPlayerID {
-				return fmt.Errorf("not_your_turn")
-			}
+	game, err := store.loadGame(gameID)
+	if err != nil {
+		return nil, err
+	}
+	game = store.dealOverdueSeriesGame(game)
 
-			fmt.Println("actionType", actionType)
-
-			switch actionType.name {
-			case "income":
-				turnPlayer.Coins++
-				game.TurnIndex = (game.TurnIndex + 1) % len(game.Players)
-			case "foreign_aid":
-				// Criar PendingAction para foreign aid
-				// Broad cast do PendingAction para todos os players
-			case "coup":
-				if turnPlayer.Coins < 7 {
-					return fmt.Errorf("not_enough_coins")
-				}
-
-				if actionType.targetPlayerID == nil {
-					return fmt.Errorf("missing_target_player")
-				}
-
-				var targetPlayer *Player
-				for i := range game.Players {
-					if game.Players[i].ID == *actionType.targetPlayerID {
-						targetPlayer = game.Players[i]
-						break
-					}
-				}
-
-				if targetPlayer == nil {
-					return fmt.Errorf("target_player_not_found")
-				}
-
-				// Se o alvo não tiver nenhuma influência revelada, não pode ser morto
-				if targetPlayer.Influences[0].Revealed && targetPlayer.Influences[1].Revealed {
-					return fmt.Errorf("target_player_is_dead")
-				}
-
-				if !targetPlayer.Influences[0].Revealed && !targetPlayer.Influences[1].Revealed {
-					// Criar evento pendente de golpe de estado (alvo deve escolher uma influência para revelar)
-				}
-
-				turnPlayer.Coins -= 7
-				game.TurnIndex = (game.TurnIndex + 1) % len(game.Players)
-			}
+	return game.PrivateViewFor(session.PlayerID)
+}
 
-			updatedJSON, _ := json.Marshal(&game)
+// LeaveRoom takes the player behind sessionToken out of the room and ends their session,
+// deleting the room once its last player leaves.
+func (store *Store) LeaveRoom(gameID, sessionToken string) error {
+	session, err := store.getSession(gameID, sessionToken)
+	if err != nil {
+		return err
+	}
 
-			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-				pipe.Set(ctx, gameKey, updatedJSON, 0)
-				return nil
-			})
+	resultGame, err := store.applyCommand(gameID, engine.LeaveGame{PlayerID: session.PlayerID})
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return err
-			}
+	if err := store.invalidatePlayerSession(session.PlayerID); err != nil {
+		return err
+	}
+	realtime.Manager.DisconnectPlayer(gameID, session.PlayerID)
 
-			// guardar cópia final pra retornar
-			resultGame = game
-			return nil
-		}, gameKey)
+	// Nobody is left to hand the admin over to.
+	if resultGame.AdminID == "" {
+		return store.deleteRoom(resultGame)
+	}
 
-		BroadcastEvent(
-			resultGame.GetPublicGameState(),
-			"action_declared",
-			map[string]any{
-				"actionName":      actionType.name,
-				"isImmediate":     actionType.isImmediate,
-				"isBlockable":     actionType.isBlockable,
-				"isContestable":   actionType.isContestable,
-				"requiresTarget":  actionType.requiresTarget,
-				"targetPlayerID":  actionType.targetPlayerID,
-				"bloackableRoles": actionType.bloackableRoles,
-			},
-		)
+	return nil
+}
 
-		if err == redis.TxFailedErr {
-			continue
-		}
-		if err != nil {
-			return nil, err
-		}
-		break
+// KickPlayer lets the admin behind sessionToken remove targetID from the lobby, ending the target's session.
+func (store *Store) KickPlayer(gameID, sessionToken, targetID string) (*engine.PublicGameState, error) {
+	gameState, err := store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.KickPlayer{PlayerID: playerID, TargetID: targetID}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	publicGameState := resultGame.GetPublicGameState()
-	return publicGameState, nil
+	if err := store.invalidatePlayerSession(targetID); err != nil {
+		return nil, err
+	}
+	realtime.Manager.DisconnectPlayer(gameID, targetID)
+
+	return gameState, nil
 }
 
-func buildActionType(action DeclareActionPayload) (*ActionType, error) {
-	var actionType ActionType
-	switch action.ActionName {
-	case "income":
-		actionType = ActionType{
-			name:            "income",
-			isImmediate:     true,
-			isBlockable:     false,
-			isContestable:   false,
-			requiresTarget:  false,
-			targetPlayerID:  nil,
-			bloackableRoles: []Influence{},
-		}
-	case "foreign_aid":
-		actionType = ActionType{
-			name:           "foreign_aid",
-			isImmediate:    true,
-			isBlockable:    true,
-			isContestable:  false,
-			requiresTarget: false,
-			targetPlayerID: nil,
-			bloackableRoles: []Influence{
-				{Role: "Duke"},
-			},
-		}
-	case "coup":
-		if action.TargetPlayerID == nil {
-			return nil, errors.New("target_player_is_required")
-		}
-		actionType = ActionType{
-			name:            "coup",
-			isImmediate:     true,
-			isBlockable:     false,
-			isContestable:   false,
-			requiresTarget:  true,
-			targetPlayerID:  action.TargetPlayerID,
-			bloackableRoles: []Influence{},
-		}
-	// TODO: Add role actions (tax, assassinate, steal, exchange)
-	default:
-		return nil, errors.New("invalid_action_name")
-	}
-	return &actionType, nil
+// Forfeit gives up the game in progress for the player behind sessionToken, who stays in the room as eliminated.
+func (store *Store) Forfeit(gameID, sessionToken string) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.Forfeit{PlayerID: playerID}
+	})
+}
+
+// UpdateSettings lets the admin behind sessionToken change the room settings before the game starts.
+func (store *Store) UpdateSettings(gameID, sessionToken string, update engine.SettingsUpdate) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.UpdateSettings{PlayerID: playerID, Update: update}
+	})
+}
+
+// ShowInfluence picks the card the player behind sessionToken shows to the Inquisitor examining them.
+func (store *Store) ShowInfluence(gameID, sessionToken string, influenceIndex int) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.ShowInfluence{PlayerID: playerID, InfluenceIndex: influenceIndex}
+	})
+}
+
+// DecideExamination lets the Inquisitor behind sessionToken decide whether the examined card has to be exchanged.
+func (store *Store) DecideExamination(gameID, sessionToken string, forceExchange bool) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.DecideExamination{PlayerID: playerID, ForceExchange: forceExchange}
+	})
+}
+
+// DraftInfluence picks the first influence of the duel player behind sessionToken.
+func (store *Store) DraftInfluence(gameID, sessionToken, role string) (*engine.PublicGameState, error) {
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.DraftInfluence{PlayerID: playerID, Role: role}
+	})
+}
+
+// Rematch lets the admin behind sessionToken deal a new game to the same room once the last one is over.
+func (store *Store) Rematch(gameID, sessionToken string, winnerStarts bool) (*engine.PublicGameState, error) {
+	seed := store.newSeed()
+
+	return store.applyPlayerCommand(gameID, sessionToken, func(playerID string) engine.Command {
+		return engine.Rematch{PlayerID: playerID, Seed: seed, WinnerStarts: winnerStarts}
+	})
 }
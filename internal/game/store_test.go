@@ -0,0 +1,79 @@
+package game
+
+import (
+	"influence_game/internal/game/engine"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) *Store {
+	server := miniredis.RunT(t)
+	return NewStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+}
+
+func TestStoreSeedsVerifyAgainstTheDeckCommitment(t *testing.T) {
+	store := NewStore(nil)
+
+	seed := store.newSeed()
+	if seed == store.newSeed() {
+		t.Fatalf("two games should not be dealt the same seed")
+	}
+
+	game := engine.NewGame("game", "CODE42", engine.NewPlayer("p0", "player 0"), engine.DefaultSettings(), time.Now())
+	game, _, err := engine.Apply(game, engine.JoinGame{PlayerID: "p1", Nickname: "player 1"})
+	if err != nil {
+		t.Fatalf("unexpected error joining: %v", err)
+	}
+	game, _, err = engine.Apply(game, engine.StartGame{PlayerID: "p0", Seed: seed})
+	if err != nil {
+		t.Fatalf("unexpected error starting: %v", err)
+	}
+
+	if !engine.VerifyDeal(game.DeckCommitment, seed, len(game.Players), engine.DeckVariantBase) {
+		t.Fatalf("the seed drawn by the store should verify")
+	}
+}
+
+func TestFixedRandSourceReplaysTheSameRoom(t *testing.T) {
+	deal := func() (string, *engine.Game) {
+		store := newTestStore(t)
+		store.SetRandSource(rand.NewSource(7))
+
+		room, err := store.CreateGameRoom("admin", engine.DefaultSettings())
+		if err != nil {
+			t.Fatalf("unexpected error creating the room: %v", err)
+		}
+		if _, err := store.Join(room.Game.JoinCode, "guest"); err != nil {
+			t.Fatalf("unexpected error joining: %v", err)
+		}
+		if _, err := store.StartGame(room.Game.GameID, room.Token); err != nil {
+			t.Fatalf("unexpected error starting: %v", err)
+		}
+
+		game, err := store.loadGame(room.Game.GameID)
+		if err != nil {
+			t.Fatalf("unexpected error loading: %v", err)
+		}
+		return room.Game.JoinCode, game
+	}
+
+	firstCode, first := deal()
+	secondCode, second := deal()
+
+	if firstCode != secondCode || first.Seed != second.Seed || first.TurnIndex != second.TurnIndex {
+		t.Fatalf("the same source should give the same join code, seed and starting player")
+	}
+	for i := range first.Players {
+		if !reflect.DeepEqual(first.Players[i].Influences, second.Players[i].Influences) {
+			t.Fatalf("seat %d was dealt %v then %v", i, first.Players[i].Influences, second.Players[i].Influences)
+		}
+	}
+	if !reflect.DeepEqual(first.Deck, second.Deck) {
+		t.Fatalf("the same source should leave the same deck")
+	}
+}
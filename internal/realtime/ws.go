@@ -10,6 +10,22 @@ type Client struct {
 	Conn     *websocket.Conn
 	GameID   string
 	PlayerID string
+
+	writeMu sync.Mutex
+}
+
+/*
+WriteMessage sends msg as a text message to the client. Events are published both from
+HTTP handlers and from timers, so writes to one client are serialized.
+
+⚠️ Warning:
+- always write through here, gorilla/websocket panics on concurrent writes to one connection
+*/
+func (c *Client) WriteMessage(msg []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.Conn.WriteMessage(websocket.TextMessage, msg)
 }
 
 type RoomManager struct {
@@ -55,6 +71,56 @@ func (m *RoomManager) Broadcast(gameID string, msg []byte) {
 	m.mu.RUnlock()
 
 	for _, c := range clients {
-		_ = c.Conn.WriteMessage(websocket.TextMessage, msg)
+		_ = c.WriteMessage(msg)
+	}
+}
+
+// BroadcastPerPlayer writes to every connection in gameID the message built for its player.
+// A nil message skips that player.
+func (m *RoomManager) BroadcastPerPlayer(gameID string, buildMessage func(playerID string) []byte) {
+	m.mu.RLock()
+	clients := m.rooms[gameID]
+	m.mu.RUnlock()
+
+	messages := make(map[string][]byte)
+	for _, c := range clients {
+		msg, ok := messages[c.PlayerID]
+		if !ok {
+			msg = buildMessage(c.PlayerID)
+			messages[c.PlayerID] = msg
+		}
+		if msg == nil {
+			continue
+		}
+		_ = c.WriteMessage(msg)
+	}
+}
+
+// SendToPlayer writes msg only to the connections of playerID in gameID.
+func (m *RoomManager) SendToPlayer(gameID string, playerID string, msg []byte) {
+	m.mu.RLock()
+	clients := m.rooms[gameID]
+	m.mu.RUnlock()
+
+	for _, c := range clients {
+		if c.PlayerID != playerID {
+			continue
+		}
+		_ = c.WriteMessage(msg)
+	}
+}
+
+// DisconnectPlayer closes every connection of playerID in gameID.
+// The read loop of each connection notices the close and removes the client.
+func (m *RoomManager) DisconnectPlayer(gameID string, playerID string) {
+	m.mu.RLock()
+	clients := m.rooms[gameID]
+	m.mu.RUnlock()
+
+	for _, c := range clients {
+		if c.PlayerID != playerID {
+			continue
+		}
+		_ = c.Conn.Close()
 	}
 }